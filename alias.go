@@ -0,0 +1,125 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// fieldAliases returns map of alias names to JSON field names for struct
+// fields of v tagged with `aliases`, for example:
+//
+//	FirstName string `json:"first_name" aliases:"firstName,fname"`
+func fieldAliases(v interface{}) map[string]string {
+	rt := reflect.TypeOf(v)
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil
+	}
+
+	aliases := map[string]string{}
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag := field.Tag.Get("aliases")
+		if tag == "" {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		for _, alias := range strings.Split(tag, ",") {
+			if alias = strings.TrimSpace(alias); alias != "" && alias != name {
+				aliases[alias] = name
+			}
+		}
+	}
+	return aliases
+}
+
+// resolveAliases renames alias keys in JSON object data to field names. Keys
+// already present under field name take precedence over aliases.
+func resolveAliases(data []byte, aliases map[string]string) ([]byte, error) {
+	object := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		// not an object, leave it to decoder to report the error
+		return data, nil //nolint:nilerr
+	}
+
+	for alias, name := range aliases {
+		value, ok := object[alias]
+		if !ok {
+			continue
+		}
+		if _, exists := object[name]; !exists {
+			object[name] = value
+		}
+		delete(object, alias)
+	}
+	return json.Marshal(object)
+}
+
+// jsonFormNames returns map of JSON names to field names for struct fields of
+// v which have `json` tag and no `form` tag.
+func jsonFormNames(v interface{}) map[string]string {
+	rt := reflect.TypeOf(v)
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil
+	}
+
+	names := map[string]string{}
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if _, ok := field.Tag.Lookup("form"); ok {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" && name != field.Name {
+			names[name] = field.Name
+		}
+	}
+	return names
+}
+
+// resolveFormNames renames form keys using names map.
+func resolveFormNames(values url.Values, names map[string]string) url.Values {
+	for name, fieldName := range names {
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+		if _, exists := values[fieldName]; !exists {
+			values[fieldName] = value
+		}
+		delete(values, name)
+	}
+	return values
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"net/http"
+	"strings"
+
+	"golang.org/x/text/encoding/ianaindex"
+)
+
+// AcceptCharsetHeader represents Accept-Charset key in header
+const AcceptCharsetHeader = "Accept-Charset"
+
+// CharsetTranscoding enables transcoding of PlainText and HTML responses to
+// charset negotiated with request Accept-Charset header, for example
+// ISO-8859-1. Responses are UTF-8 encoded when UTF-8 is acceptable or header
+// is missing.
+var CharsetTranscoding = false
+
+// transcodeText encodes UTF-8 text b to charset accepted by request r, or
+// request rendered to w when r is nil, and returns encoded text with content
+// type ct labeled with the charset. False is returned when none of the
+// accepted charsets is supported and Negotiation is NegotiationStrict.
+func transcodeText(w http.ResponseWriter, r *http.Request, b []byte, ct string) ([]byte, string, bool) {
+	if rw, ok := w.(*responseWriter); ok && r == nil {
+		r = rw.r
+	}
+	if !CharsetTranscoding || r == nil {
+		return b, ct, true
+	}
+	ranges := parseAccept(r.Header.Get(AcceptCharsetHeader))
+	if len(ranges) == 0 {
+		return b, ct, true
+	}
+
+	for _, mr := range ranges {
+		if mr.q <= 0 {
+			continue
+		}
+		if mr.value == "utf-8" || mr.value == "*" {
+			return b, ct, true
+		}
+		enc, err := ianaindex.MIME.Encoding(mr.value)
+		if err != nil || enc == nil {
+			continue
+		}
+		name, err := ianaindex.MIME.Name(enc)
+		if err != nil {
+			continue
+		}
+		encoded, err := enc.NewEncoder().Bytes(b)
+		if err != nil {
+			// text can't be represented in this charset
+			continue
+		}
+		mediaType := strings.TrimSpace(strings.Split(ct, ";")[0])
+		return encoded, mediaType + "; charset=" + name, true
+	}
+
+	if Negotiation == NegotiationStrict {
+		return nil, "", false
+	}
+	return b, ct, true
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
+)
+
+func TestCharsetTranscoding(t *testing.T) {
+	render.CharsetTranscoding = true
+	defer func() {
+		render.CharsetTranscoding = false
+	}()
+
+	tests := []struct {
+		name          string
+		policy        render.NegotiationPolicy
+		acceptCharset string
+		status        int
+		contentType   string
+		body          string
+	}{
+		{
+			name:          "iso-8859-1",
+			acceptCharset: "iso-8859-1",
+			status:        http.StatusOK,
+			contentType:   "text/plain; charset=ISO-8859-1",
+			body:          "Caf\xe9 Z\xfcrich",
+		},
+		{
+			name:          "utf-8 preferred",
+			acceptCharset: "iso-8859-1;q=0.5, utf-8",
+			status:        http.StatusOK,
+			contentType:   "text/plain; charset=utf-8",
+			body:          "Café Zürich",
+		},
+		{
+			name:          "lenient - unsupported charset",
+			acceptCharset: "x-unknown",
+			status:        http.StatusOK,
+			contentType:   "text/plain; charset=utf-8",
+			body:          "Café Zürich",
+		},
+		{
+			name:          "strict - unsupported charset",
+			policy:        render.NegotiationStrict,
+			acceptCharset: "x-unknown",
+			status:        http.StatusNotAcceptable,
+			contentType:   "text/plain; charset=utf-8",
+			body:          render.ErrNotAcceptable.Error() + "\n",
+		},
+	}
+
+	refNegotiation := render.Negotiation
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			render.Negotiation = tt.policy
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set(render.AcceptHeader, render.TextPlain)
+			r.Header.Set(render.AcceptCharsetHeader, tt.acceptCharset)
+			w := httptest.NewRecorder()
+
+			render.Render(w, r, "Café Zürich")
+
+			utest.Equals(t, tt.status, w.Code)
+			utest.Equals(t, tt.contentType, w.Header().Get(render.ContentTypeHeader))
+			utest.Equals(t, tt.body, w.Body.String())
+		})
+	}
+	render.Negotiation = refNegotiation
+}
+
+func TestCharsetTranscoding_HTML(t *testing.T) {
+	render.CharsetTranscoding = true
+	defer func() {
+		render.CharsetTranscoding = false
+	}()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(render.AcceptCharsetHeader, "iso-8859-1")
+	w := httptest.NewRecorder()
+
+	render.HTML(w, "<p>Café Zürich</p>", r)
+
+	utest.Equals(t, http.StatusOK, w.Code)
+	utest.Equals(t, "text/html; charset=ISO-8859-1", w.Header().Get(render.ContentTypeHeader))
+	utest.Equals(t, "<p>Caf\xe9 Z\xfcrich</p>", w.Body.String())
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+)
+
+// Header names used for response compression
+const (
+	AcceptEncodingHeader  = "Accept-Encoding"
+	ContentEncodingHeader = "Content-Encoding"
+	VaryHeader            = "Vary"
+)
+
+// Content codings handled by this package.
+const (
+	EncodingGzip     = "gzip"
+	EncodingIdentity = "identity"
+)
+
+var (
+	// Compression enables gzip compression of rendered responses based on
+	// request Accept-Encoding header.
+	Compression = false
+	// CompressionMinBytes is minimum size of response body to be compressed.
+	// Smaller responses are compressed only when client refuses identity
+	// encoding.
+	CompressionMinBytes = 1024
+)
+
+// encodingQuality returns quality values of gzip and identity content codings
+// from request Accept-Encoding header. Identity is acceptable unless excluded
+// by `identity;q=0` or `*;q=0`.
+func encodingQuality(r *http.Request) (gzipQ, identityQ float64) {
+	gzipQ, identityQ, wildcardQ := -1.0, -1.0, -1.0
+	for _, mr := range parseAccept(r.Header.Get(AcceptEncodingHeader)) {
+		switch mr.value {
+		case EncodingGzip, "x-gzip":
+			if gzipQ < 0 {
+				gzipQ = mr.q
+			}
+		case EncodingIdentity:
+			if identityQ < 0 {
+				identityQ = mr.q
+			}
+		case "*":
+			if wildcardQ < 0 {
+				wildcardQ = mr.q
+			}
+		}
+	}
+
+	if gzipQ < 0 {
+		gzipQ = wildcardQ
+	}
+	if identityQ < 0 {
+		identityQ = 1
+		if wildcardQ == 0 {
+			identityQ = 0
+		}
+	}
+	return gzipQ, identityQ
+}
+
+// acceptedEncoding returns content coding for response body of size bytes
+// based on Accept-Encoding header q-values. False is returned when neither
+// gzip nor identity is acceptable.
+func acceptedEncoding(r *http.Request, size int) (string, bool) {
+	gzipQ, identityQ := encodingQuality(r)
+	switch {
+	case gzipQ > 0 && (size >= CompressionMinBytes || identityQ <= 0):
+		return EncodingGzip, true
+	case identityQ > 0:
+		return EncodingIdentity, true
+	default:
+		return "", false
+	}
+}
+
+// compressBody gzips response body v when Compression is enabled and
+// request rendered to w accepts it. False is returned when request refuses
+// every encoding we can produce.
+func compressBody(w http.ResponseWriter, v []byte) ([]byte, bool) {
+	rw, ok := w.(*responseWriter)
+	if !Compression || !ok || w.Header().Get(ContentEncodingHeader) != "" {
+		return v, true
+	}
+	w.Header().Add(VaryHeader, AcceptEncodingHeader)
+
+	encoding, ok := acceptedEncoding(rw.r, len(v))
+	if !ok {
+		return nil, false
+	}
+	if encoding != EncodingGzip {
+		return v, true
+	}
+
+	buf := &bytes.Buffer{}
+	zw := gzip.NewWriter(buf)
+	if _, err := zw.Write(v); err != nil {
+		return v, true
+	}
+	if err := zw.Close(); err != nil {
+		return v, true
+	}
+	w.Header().Set(ContentEncodingHeader, EncodingGzip)
+	w.Header().Del("Content-Length")
+	return buf.Bytes(), true
+}
+
+// compressibleType reports whether content type ct is text-like and worth
+// compressing.
+func compressibleType(ct string) bool {
+	ct = strings.TrimSpace(strings.Split(ct, ";")[0])
+	switch {
+	case strings.HasPrefix(ct, "text/"),
+		strings.HasSuffix(ct, "+json"),
+		strings.HasSuffix(ct, "+xml"):
+		return true
+	}
+	switch ct {
+	case ApplicationJSON, ApplicationXML, ApplicationTOML, "application/javascript", "image/svg+xml":
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter compresses successful responses, error responses are
+// written uncompressed.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	zw          *gzip.Writer
+	compress    bool
+	wroteHeader bool
+}
+
+// WriteHeader sets Content-Encoding header for 200 OK responses and sends
+// response header.
+func (gw *gzipResponseWriter) WriteHeader(status int) {
+	if gw.wroteHeader {
+		return
+	}
+	gw.wroteHeader = true
+	if status == http.StatusOK {
+		gw.compress = true
+		gw.Header().Del("Content-Length")
+		gw.Header().Set(ContentEncodingHeader, EncodingGzip)
+	}
+	gw.ResponseWriter.WriteHeader(status)
+}
+
+// Write compresses b when response is compressed.
+func (gw *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !gw.wroteHeader {
+		gw.WriteHeader(http.StatusOK)
+	}
+	if !gw.compress {
+		return gw.ResponseWriter.Write(b)
+	}
+	if gw.zw == nil {
+		gw.zw = gzip.NewWriter(gw.ResponseWriter)
+	}
+	return gw.zw.Write(b)
+}
+
+// Close flushes compressed data, it must be called after response is written.
+func (gw *gzipResponseWriter) Close() error {
+	if gw.zw == nil {
+		return nil
+	}
+	return gw.zw.Close()
+}
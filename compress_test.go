@@ -0,0 +1,88 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
+)
+
+func TestCompression(t *testing.T) {
+	render.Compression = true
+	defer func() {
+		render.Compression = false
+	}()
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		status         int
+		encoding       string
+	}{
+		{
+			name:           "small body is not compressed",
+			acceptEncoding: "gzip",
+			status:         http.StatusOK,
+		},
+		{
+			name:           "identity refused",
+			acceptEncoding: "identity;q=0, gzip",
+			status:         http.StatusOK,
+			encoding:       render.EncodingGzip,
+		},
+		{
+			name:           "wildcard refused",
+			acceptEncoding: "*;q=0, gzip;q=0.5",
+			status:         http.StatusOK,
+			encoding:       render.EncodingGzip,
+		},
+		{
+			name:           "nothing acceptable",
+			acceptEncoding: "identity;q=0, gzip;q=0",
+			status:         http.StatusNotAcceptable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set(render.AcceptEncodingHeader, tt.acceptEncoding)
+
+			render.Render(w, r, map[string]string{"name": "Enver"})
+
+			utest.Equals(t, tt.status, w.Code)
+			utest.Equals(t, tt.encoding, w.Header().Get(render.ContentEncodingHeader))
+			if tt.encoding != render.EncodingGzip {
+				return
+			}
+			zr, err := gzip.NewReader(w.Body)
+			utest.OK(t, err)
+			body, err := io.ReadAll(zr)
+			utest.OK(t, err)
+			utest.Equals(t, "{\"name\":\"Enver\"}\n", string(body))
+		})
+	}
+}
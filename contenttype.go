@@ -21,6 +21,8 @@ package render
 
 import (
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -30,16 +32,41 @@ const (
 	ApplicationXHTML   = "application/xhtml+xml"
 	ApplicationJSON    = "application/json"
 	ApplicationJSONExt = "application/json; charset=utf-8"
+	ApplicationLDJSON  = "application/ld+json"
 	ApplicationFormURL = "application/x-www-form-urlencoded"
+	ApplicationTOML    = "application/toml"
 	TextPlain          = "text/plain"
 	TextHTML           = "text/html"
 	TextXML            = "text/xml"
 	TextJavascript     = "text/javascript"
 	TextEventStream    = "text/event-stream"
+	TextCSV            = "text/csv"
 )
 
-// DefaultContentType is a package-level variable set to our default content type
-var DefaultContentType = ContentTypeJSON
+var (
+	// DefaultContentType is a package-level variable set to our default content type
+	DefaultContentType = ContentTypeJSON
+	// NoAcceptDefault is content type used for requests without Accept
+	// header, DefaultContentType is used when it is ContentTypeUnknown.
+	NoAcceptDefault = ContentTypeUnknown
+	// Negotiation is a package-level variable set to our default negotiation
+	// policy.
+	Negotiation = NegotiationLenient
+)
+
+// NegotiationPolicy defines how unknown `format` query param values and
+// unknown Accept headers are handled.
+type NegotiationPolicy int
+
+// Negotiation policies handled by this package.
+const (
+	// NegotiationLenient ignores unknown format values and falls back to
+	// DefaultContentType for unknown Accept headers.
+	NegotiationLenient NegotiationPolicy = iota
+	// NegotiationStrict responds with 406 Not Acceptable for unknown format
+	// values and Accept headers.
+	NegotiationStrict
+)
 
 // ContentType is an enumeration of common HTTP content types.
 type ContentType int
@@ -53,6 +80,8 @@ const (
 	ContentTypeXML
 	ContentTypeForm
 	ContentTypeEventStream
+	ContentTypeCSV
+	ContentTypeTOML
 )
 
 // GetContentType returns ContentType value based on input s
@@ -63,14 +92,18 @@ func GetContentType(s string) ContentType {
 		return ContentTypePlainText
 	case TextHTML, ApplicationXHTML:
 		return ContentTypeHTML
-	case ApplicationJSON, TextJavascript:
+	case ApplicationJSON, ApplicationLDJSON, ApplicationProblemJSON, TextJavascript:
 		return ContentTypeJSON
-	case TextXML, ApplicationXML:
+	case TextXML, ApplicationXML, ApplicationProblemXML:
 		return ContentTypeXML
 	case ApplicationFormURL:
 		return ContentTypeForm
 	case TextEventStream:
 		return ContentTypeEventStream
+	case TextCSV:
+		return ContentTypeCSV
+	case ApplicationTOML:
+		return ContentTypeTOML
 	default:
 		return ContentTypeUnknown
 	}
@@ -82,18 +115,179 @@ func GetRequestContentType(r *http.Request) ContentType {
 	return GetContentType(r.Header.Get(ContentTypeHeader))
 }
 
+// acceptable is the list of media types matched against Accept wildcards,
+// ordered by preference.
+var acceptable = []string{
+	ApplicationJSON,
+	ApplicationXML,
+	TextPlain,
+	TextHTML,
+}
+
+// mediaRange is a single Accept header entry with its quality value and
+// media type parameters.
+type mediaRange struct {
+	value  string
+	q      float64
+	params map[string]string
+}
+
+// parseAccept parses Accept header value into media ranges sorted by
+// quality value, highest first.
+func parseAccept(header string) []mediaRange {
+	var ranges []mediaRange
+	for _, field := range strings.Split(header, ",") {
+		parts := strings.Split(field, ";")
+		value := strings.ToLower(strings.TrimSpace(parts[0]))
+		if value == "" {
+			continue
+		}
+		q := 1.0
+		params := map[string]string{}
+		for _, param := range parts[1:] {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			key := strings.ToLower(strings.TrimSpace(kv[0]))
+			if key != "q" {
+				params[key] = strings.Trim(strings.TrimSpace(kv[1]), `"`)
+				continue
+			}
+			if f, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64); err == nil {
+				q = f
+			}
+		}
+		ranges = append(ranges, mediaRange{value: value, q: q, params: params})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+	return ranges
+}
+
+// acceptedJSON returns preferred JSON media range from request Accept header.
+func acceptedJSON(r *http.Request) (mediaRange, bool) {
+	for _, mr := range parseAccept(r.Header.Get(AcceptHeader)) {
+		if mr.q > 0 && GetContentType(mr.value) == ContentTypeJSON {
+			return mr, true
+		}
+	}
+	return mediaRange{}, false
+}
+
+// wildcardContentTypes returns content types matching wildcard media range
+// like */* or text/*.
+func wildcardContentTypes(value string) []ContentType {
+	var types []ContentType
+	if value == "*/*" || value == "*" {
+		types = append(types, DefaultContentType)
+	}
+	prefix := strings.TrimSuffix(value, "*")
+	for _, mime := range acceptable {
+		if prefix == "" || prefix == "*/" || strings.HasPrefix(mime, prefix) {
+			types = append(types, GetContentType(mime))
+		}
+	}
+	return types
+}
+
+// availableContentTypes is the list of content types rendered by
+// DefaultResponder.
+var availableContentTypes = []ContentType{
+	ContentTypeJSON,
+	ContentTypeXML,
+	ContentTypePlainText,
+	ContentTypeEventStream,
+	ContentTypeCSV,
+	ContentTypeTOML,
+}
+
+// Negotiator is a package-level variable set to function used by
+// DefaultResponder to choose response content type from available content
+// types. It allows replacing content negotiation entirely, for example:
+//
+//	render.Negotiator = func(r *http.Request, available []render.ContentType) render.ContentType {
+//		return negotiate(r.Header.Get(render.AcceptHeader), available)
+//	}
+//
+// Returning ContentTypeUnknown is handled according to Negotiation policy.
+var Negotiator = DefaultNegotiator
+
+// DefaultNegotiator returns content type from available with the highest
+// quality value in request Accept header. Accepted types which are not
+// available are skipped, when none matches ContentTypeUnknown is returned
+// under NegotiationStrict, otherwise DefaultContentType. See
+// GetAcceptedContentType.
+func DefaultNegotiator(r *http.Request, available []ContentType) ContentType {
+	return negotiateContentType(r, available)
+}
+
 // GetAcceptedContentType reads Accept header from request and returns ContentType
+// rendered by DefaultResponder with the highest quality value. Media ranges
+// with q=0 are excluded. If none of the most preferred types can be rendered
+// DefaultContentType is returned, while NegotiationStrict tries less
+// preferred types and returns ContentTypeUnknown when none can be rendered.
+// ContentTypeHTML and ContentTypeForm are never returned, as DefaultResponder
+// doesn't render them. NoAcceptDefault is returned for requests without
+// Accept header, when set.
 func GetAcceptedContentType(r *http.Request) ContentType {
-	var contentType ContentType
+	return negotiateContentType(r, availableContentTypes)
+}
+
+// negotiateContentType returns content type from available with the highest
+// quality value in request Accept header, see GetAcceptedContentType.
+func negotiateContentType(r *http.Request, available []ContentType) ContentType {
+	isAvailable := func(contentType ContentType) bool {
+		for _, ct := range available {
+			if ct == contentType {
+				return true
+			}
+		}
+		return false
+	}
+
+	ranges := parseAccept(r.Header.Get(AcceptHeader))
+	if len(ranges) == 0 {
+		if NoAcceptDefault != ContentTypeUnknown {
+			return NoAcceptDefault
+		}
+		return DefaultContentType
+	}
+
+	excluded := map[ContentType]bool{}
+	for _, mr := range ranges {
+		if mr.q <= 0 && !strings.Contains(mr.value, "*") {
+			excluded[GetContentType(mr.value)] = true
+		}
+	}
 
-	// Parse request Accept header.
-	fields := strings.Split(r.Header.Get(AcceptHeader), ",")
-	if len(fields) > 0 {
-		contentType = GetContentType(strings.TrimSpace(fields[0]))
+	top := ranges[0].q
+	for _, mr := range ranges {
+		if mr.q <= 0 {
+			continue
+		}
+		// Lenient negotiation doesn't pick less preferred type when none
+		// of the most preferred ones can be rendered, for example browsers
+		// preferring text/html get DefaultContentType.
+		if mr.q < top && Negotiation != NegotiationStrict {
+			break
+		}
+		if strings.Contains(mr.value, "*") {
+			for _, contentType := range wildcardContentTypes(mr.value) {
+				if !excluded[contentType] && isAvailable(contentType) {
+					return contentType
+				}
+			}
+			continue
+		}
+		if contentType := GetContentType(mr.value); isAvailable(contentType) && !excluded[contentType] {
+			return contentType
+		}
 	}
 
-	if contentType == ContentTypeUnknown {
-		contentType = DefaultContentType
+	if Negotiation == NegotiationStrict {
+		return ContentTypeUnknown
 	}
-	return contentType
+	return DefaultContentType
 }
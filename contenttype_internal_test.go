@@ -0,0 +1,62 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestRegisterFormat(t *testing.T) {
+	refFormats := make(map[string][]string, len(formats))
+	for name, values := range formats {
+		refFormats[name] = values
+	}
+	defer func() {
+		formats = refFormats
+	}()
+
+	RegisterFormat("text", TextPlain)
+
+	r := &http.Request{
+		URL: &url.URL{RawQuery: "format=text"},
+		Header: http.Header{
+			AcceptHeader: []string{ApplicationXML},
+		},
+	}
+	w := httptest.NewRecorder()
+
+	Render(w, r, "Point")
+
+	if got := r.Header.Get(AcceptHeader); got != TextPlain {
+		t.Errorf("Accept = %q, want %q", got, TextPlain)
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got := w.Header().Get(ContentTypeHeader); got != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want %q", got, "text/plain; charset=utf-8")
+	}
+	if got := w.Body.String(); got != "Point" {
+		t.Errorf("body = %q, want %q", got, "Point")
+	}
+}
@@ -20,10 +20,14 @@
 package render_test
 
 import (
+	"encoding/xml"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
 )
 
 func TestGetContentType(t *testing.T) {
@@ -146,6 +150,39 @@ func TestGetAcceptedContentType(t *testing.T) {
 			},
 			want: render.ContentTypeJSON,
 		},
+		{
+			name: "highest quality value wins",
+			args: args{
+				r: &http.Request{
+					Header: http.Header{
+						"Accept": []string{"application/json;q=0.5, application/xml"},
+					},
+				},
+			},
+			want: render.ContentTypeXML,
+		},
+		{
+			name: "q=0 excludes content type from wildcard",
+			args: args{
+				r: &http.Request{
+					Header: http.Header{
+						"Accept": []string{"application/json;q=0, */*"},
+					},
+				},
+			},
+			want: render.ContentTypeXML,
+		},
+		{
+			name: "all types excluded falls back to default",
+			args: args{
+				r: &http.Request{
+					Header: http.Header{
+						"Accept": []string{"application/json;q=0, */*;q=0"},
+					},
+				},
+			},
+			want: render.ContentTypeJSON,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -155,3 +192,262 @@ func TestGetAcceptedContentType(t *testing.T) {
 		})
 	}
 }
+
+func TestNoAcceptDefault(t *testing.T) {
+	refNoAcceptDefault := render.NoAcceptDefault
+	render.NoAcceptDefault = render.ContentTypePlainText
+	defer func() {
+		render.NoAcceptDefault = refNoAcceptDefault
+	}()
+
+	r := &http.Request{Header: http.Header{}}
+	utest.Equals(t, render.ContentTypePlainText, render.GetAcceptedContentType(r))
+
+	r.Header.Set(render.AcceptHeader, "garbage")
+	utest.Equals(t, render.DefaultContentType, render.GetAcceptedContentType(r))
+}
+
+func TestNegotiationStrict(t *testing.T) {
+	r := &http.Request{
+		URL: &url.URL{},
+		Header: http.Header{
+			render.AcceptHeader: []string{"application/json;q=0, */*;q=0"},
+		},
+	}
+
+	refNegotiation := render.Negotiation
+	render.Negotiation = render.NegotiationStrict
+
+	w := httptest.NewRecorder()
+	render.Render(w, r, map[string]string{"name": "Enver"})
+	utest.Equals(t, http.StatusNotAcceptable, w.Code)
+
+	render.Negotiation = render.NegotiationLenient
+
+	w = httptest.NewRecorder()
+	render.Render(w, r, map[string]string{"name": "Enver"})
+	utest.Equals(t, http.StatusOK, w.Code)
+	utest.Equals(t, render.ApplicationJSONExt, w.Header().Get(render.ContentTypeHeader))
+	utest.Equals(t, `{"name":"Enver"}`+"\n", w.Body.String())
+
+	render.Negotiation = refNegotiation
+}
+
+func TestNegotiationPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      render.NegotiationPolicy
+		format      string
+		accept      string
+		status      int
+		contentType string
+	}{
+		{
+			name:        "lenient - unknown format, known accept",
+			policy:      render.NegotiationLenient,
+			format:      "unknown",
+			accept:      render.ApplicationXML,
+			status:      http.StatusOK,
+			contentType: "application/xml; charset=utf-8",
+		},
+		{
+			name:        "lenient - known format, unknown accept",
+			policy:      render.NegotiationLenient,
+			format:      "xml",
+			accept:      "unknown",
+			status:      http.StatusOK,
+			contentType: "application/xml; charset=utf-8",
+		},
+		{
+			name:        "lenient - unknown format, unknown accept",
+			policy:      render.NegotiationLenient,
+			format:      "unknown",
+			accept:      "unknown",
+			status:      http.StatusOK,
+			contentType: render.ApplicationJSONExt,
+		},
+		{
+			name:        "strict - unknown format, known accept",
+			policy:      render.NegotiationStrict,
+			format:      "unknown",
+			accept:      render.ApplicationXML,
+			status:      http.StatusNotAcceptable,
+			contentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:        "strict - known format, unknown accept",
+			policy:      render.NegotiationStrict,
+			format:      "xml",
+			accept:      "unknown",
+			status:      http.StatusOK,
+			contentType: "application/xml; charset=utf-8",
+		},
+		{
+			name:        "strict - no format, unknown accept",
+			policy:      render.NegotiationStrict,
+			accept:      "unknown",
+			status:      http.StatusNotAcceptable,
+			contentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:        "strict - html accept",
+			policy:      render.NegotiationStrict,
+			accept:      render.TextHTML,
+			status:      http.StatusNotAcceptable,
+			contentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:        "strict - form accept",
+			policy:      render.NegotiationStrict,
+			accept:      render.ApplicationFormURL,
+			status:      http.StatusNotAcceptable,
+			contentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:        "lenient - html accept",
+			policy:      render.NegotiationLenient,
+			accept:      render.TextHTML,
+			status:      http.StatusOK,
+			contentType: render.ApplicationJSONExt,
+		},
+		{
+			name:        "strict - unknown format, unknown accept",
+			policy:      render.NegotiationStrict,
+			format:      "unknown",
+			accept:      "unknown",
+			status:      http.StatusNotAcceptable,
+			contentType: "text/plain; charset=utf-8",
+		},
+	}
+
+	refNegotiation := render.Negotiation
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			render.Negotiation = tt.policy
+			query := url.Values{}
+			if tt.format != "" {
+				query.Set("format", tt.format)
+			}
+			r := &http.Request{
+				URL: &url.URL{RawQuery: query.Encode()},
+				Header: http.Header{
+					render.AcceptHeader: []string{tt.accept},
+				},
+			}
+			w := httptest.NewRecorder()
+
+			render.Render(w, r, "Enver")
+
+			utest.Equals(t, tt.status, w.Code)
+			utest.Equals(t, tt.contentType, w.Header().Get(render.ContentTypeHeader))
+		})
+	}
+	render.Negotiation = refNegotiation
+}
+
+func TestNegotiator(t *testing.T) {
+	refNegotiator := render.Negotiator
+	render.Negotiator = func(r *http.Request, available []render.ContentType) render.ContentType {
+		return render.ContentTypeXML
+	}
+	defer func() {
+		render.Negotiator = refNegotiator
+	}()
+
+	for _, accept := range []string{"", render.ApplicationJSON, render.TextPlain} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(render.AcceptHeader, accept)
+		w := httptest.NewRecorder()
+
+		render.Render(w, r, render.ErrorResponse{Message: "Enver"})
+
+		utest.Equals(t, "application/xml; charset=utf-8", w.Header().Get(render.ContentTypeHeader))
+		utest.Equals(t, xml.Header+"<ErrorResponse><message>Enver</message></ErrorResponse>", w.Body.String())
+	}
+}
+
+func TestDefaultNegotiator(t *testing.T) {
+	available := []render.ContentType{render.ContentTypeXML, render.ContentTypePlainText}
+	tests := []struct {
+		name   string
+		policy render.NegotiationPolicy
+		accept string
+		want   render.ContentType
+	}{
+		{
+			name:   "available type",
+			accept: "application/json, application/xml",
+			want:   render.ContentTypeXML,
+		},
+		{
+			name:   "strict - less preferred available type",
+			policy: render.NegotiationStrict,
+			accept: "application/json, text/plain;q=0.5",
+			want:   render.ContentTypePlainText,
+		},
+		{
+			name:   "strict - wildcard",
+			policy: render.NegotiationStrict,
+			accept: "application/json, text/*;q=0.5",
+			want:   render.ContentTypePlainText,
+		},
+		{
+			name:   "lenient - less preferred available type",
+			policy: render.NegotiationLenient,
+			accept: "application/json, text/plain;q=0.5",
+			want:   render.DefaultContentType,
+		},
+		{
+			name:   "lenient - nothing available",
+			policy: render.NegotiationLenient,
+			accept: render.TextHTML,
+			want:   render.DefaultContentType,
+		},
+		{
+			name:   "strict - nothing available",
+			policy: render.NegotiationStrict,
+			accept: render.TextHTML,
+			want:   render.ContentTypeUnknown,
+		},
+	}
+
+	refNegotiation := render.Negotiation
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			render.Negotiation = tt.policy
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set(render.AcceptHeader, tt.accept)
+
+			utest.Equals(t, tt.want, render.DefaultNegotiator(r, available))
+		})
+	}
+	render.Negotiation = refNegotiation
+}
+
+func TestDefaultNegotiator_BrowserAccept(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(render.AcceptHeader, "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	w := httptest.NewRecorder()
+
+	render.Render(w, r, map[string]string{"name": "Enver"})
+
+	utest.Equals(t, http.StatusOK, w.Code)
+	utest.Equals(t, render.ApplicationJSONExt, w.Header().Get(render.ContentTypeHeader))
+	utest.Equals(t, `{"name":"Enver"}`+"\n", w.Body.String())
+}
+
+func TestDefaultNegotiator_StrictHTML(t *testing.T) {
+	refNegotiation := render.Negotiation
+	render.Negotiation = render.NegotiationStrict
+	defer func() {
+		render.Negotiation = refNegotiation
+	}()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(render.AcceptHeader, render.TextHTML)
+	w := httptest.NewRecorder()
+
+	render.Render(w, r, map[string]string{"name": "Enver"})
+
+	utest.Equals(t, http.StatusNotAcceptable, w.Code)
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// utf8BOM is UTF-8 byte order mark.
+const utf8BOM = "\xef\xbb\xbf"
+
+// CSVWriteBOM prepends UTF-8 byte order mark to CSV responses, required by
+// Excel to open non-ASCII content correctly.
+var CSVWriteBOM = false
+
+// CSV writes 'v' as comma separated values to the response, setting the
+// Content-Type as text/csv. 'v' can be [][]string, struct or slice of structs,
+// for structs first row is header with names taken from `csv` tag or field
+// name.
+func CSV(w http.ResponseWriter, v interface{}, params ...interface{}) {
+	records, err := csvRecords(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	if CSVWriteBOM {
+		buf.WriteString(utf8BOM)
+	}
+	if err := csv.NewWriter(buf).WriteAll(records); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if exceedsMaxResponse(w, buf.Bytes()) {
+		return
+	}
+
+	Blob(w, buf.Bytes(), append(params, ContentTypeHeader, "text/csv; charset=utf-8")...)
+}
+
+// csvRecords converts v to CSV records.
+func csvRecords(v interface{}) ([][]string, error) {
+	if records, ok := v.([][]string); ok {
+		return records, nil
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	switch rv.Kind() {
+	case reflect.Struct:
+		return structRecords(rv.Type(), []reflect.Value{rv}), nil
+	case reflect.Slice, reflect.Array:
+		elemType := rv.Type().Elem()
+		if elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() != reflect.Struct {
+			break
+		}
+		rows := make([]reflect.Value, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			if row := reflect.Indirect(rv.Index(i)); row.IsValid() {
+				rows = append(rows, row)
+			}
+		}
+		return structRecords(elemType, rows), nil
+	}
+
+	return nil, fmt.Errorf("render: csv expects [][]string, struct or slice of structs, not %T", v)
+}
+
+// structRecords returns header and value records for exported struct fields.
+func structRecords(t reflect.Type, rows []reflect.Value) [][]string {
+	var (
+		header []string
+		fields []int
+	)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := field.Tag.Get("csv")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		header = append(header, name)
+		fields = append(fields, i)
+	}
+
+	records := make([][]string, 0, len(rows)+1)
+	records = append(records, header)
+	for _, row := range rows {
+		record := make([]string, 0, len(fields))
+		for _, i := range fields {
+			record = append(record, fmt.Sprintf("%v", row.Field(i).Interface()))
+		}
+		records = append(records, record)
+	}
+	return records
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
+)
+
+func TestCSV(t *testing.T) {
+	type user struct {
+		Name string `csv:"name"`
+		City string `csv:"city"`
+	}
+	v := []user{
+		{
+			Name: "Enver",
+			City: "Sarajevo",
+		},
+		{
+			Name: "Zoë",
+			City: "Zürich",
+		},
+	}
+	body := "name,city\nEnver,Sarajevo\nZoë,Zürich\n"
+
+	tests := []struct {
+		name string
+		bom  bool
+		body string
+	}{
+		{
+			name: "without BOM",
+			bom:  false,
+			body: body,
+		},
+		{
+			name: "with BOM",
+			bom:  true,
+			body: "\xef\xbb\xbf" + body,
+		},
+	}
+
+	refCSVWriteBOM := render.CSVWriteBOM
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			render.CSVWriteBOM = tt.bom
+			w := httptest.NewRecorder()
+
+			render.CSV(w, v)
+
+			utest.Equals(t, http.StatusOK, w.Code)
+			utest.Equals(t, "text/csv; charset=utf-8", w.Header().Get(render.ContentTypeHeader))
+			utest.Equals(t, tt.body, w.Body.String())
+		})
+	}
+	render.CSVWriteBOM = refCSVWriteBOM
+}
+
+func TestCSV_Format(t *testing.T) {
+	r := &http.Request{
+		URL:    &url.URL{RawQuery: "format=csv"},
+		Header: http.Header{},
+	}
+	w := httptest.NewRecorder()
+
+	render.Render(w, r, [][]string{{"name"}, {"Enver"}})
+
+	utest.Equals(t, "text/csv; charset=utf-8", w.Header().Get(render.ContentTypeHeader))
+	utest.Equals(t, "name\nEnver\n", w.Body.String())
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// CursorParam is query name param for cursor
+	CursorParam = "cursor"
+	// NextCursorHeader represents x-next-cursor key in header
+	NextCursorHeader = "x-next-cursor"
+	// CursorFunc returns next cursor for the last item of the page. When set
+	// CursorPagination.Render uses it for full pages without next cursor,
+	// for example:
+	//
+	//	render.CursorFunc = func(lastItem interface{}) string {
+	//		return render.EncodeCursor(lastItem.(User).ID)
+	//	}
+	CursorFunc func(lastItem interface{}) string
+	// CursorKey is secret used to sign cursors with HMAC-SHA256. Cursors are
+	// not signed when it is empty and clients can forge them.
+	CursorKey []byte
+)
+
+// EncodeCursor returns opaque cursor with v encoded as base64url JSON,
+// signed when CursorKey is set. Empty string is returned if v can't be
+// encoded.
+func EncodeCursor(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	cursor := base64.RawURLEncoding.EncodeToString(data)
+	if len(CursorKey) == 0 {
+		return cursor
+	}
+	return cursor + "." + base64.RawURLEncoding.EncodeToString(cursorSignature(cursor))
+}
+
+// DecodeCursor decodes opaque cursor s created with EncodeCursor into v.
+// ErrInvalidCursor is returned for malformed cursors and, when CursorKey is
+// set, for cursors with missing or invalid signature.
+func DecodeCursor(s string, v interface{}) error {
+	if len(CursorKey) > 0 {
+		i := strings.LastIndexByte(s, '.')
+		if i < 0 {
+			return fmt.Errorf("%w: missing signature", ErrInvalidCursor)
+		}
+		sig, err := base64.RawURLEncoding.DecodeString(s[i+1:])
+		if err != nil || !hmac.Equal(sig, cursorSignature(s[:i])) {
+			return fmt.Errorf("%w: invalid signature", ErrInvalidCursor)
+		}
+		s = s[:i]
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return nil
+}
+
+// cursorSignature returns HMAC-SHA256 of cursor signed with CursorKey.
+func cursorSignature(cursor string) []byte {
+	mac := hmac.New(sha256.New, CursorKey)
+	mac.Write([]byte(cursor)) //nolint:errcheck
+	return mac.Sum(nil)
+}
+
+// CursorPagination holds cursor (keyset) pagination data.
+type CursorPagination struct {
+	url     *url.URL
+	cursor  string
+	perPage int
+	next    string
+}
+
+// CursorPaginationFromRequest returns cursor pagination object from parsed
+// request url field.
+func CursorPaginationFromRequest(r *http.Request) CursorPagination {
+	return NewCursorPagination(r.URL)
+}
+
+// NewCursorPagination parses url and return new cursor pagination object.
+func NewCursorPagination(url *url.URL) CursorPagination {
+	queryParams := url.Query()
+
+	perPage, err := strconv.Atoi(queryParams.Get(PerPageParam))
+	if err != nil || perPage <= 0 {
+		perPage = PerPageDefault
+	}
+
+	return CursorPagination{
+		url:     url,
+		cursor:  queryParams.Get(CursorParam),
+		perPage: perPage,
+	}
+}
+
+// Cursor returns raw cursor value from request.
+func (p CursorPagination) Cursor() string {
+	return p.cursor
+}
+
+// Decode decodes request cursor into v. On the first page there is no cursor
+// and v is left untouched.
+func (p CursorPagination) Decode(v interface{}) error {
+	if p.cursor == "" {
+		return nil
+	}
+	return DecodeCursor(p.cursor, v)
+}
+
+// PerPage returns perPage (per_page) value
+func (p CursorPagination) PerPage() int {
+	return p.perPage
+}
+
+// WithNext returns copy of pagination with next cursor encoded from v.
+func (p CursorPagination) WithNext(v interface{}) CursorPagination {
+	p.next = EncodeCursor(v)
+	return p
+}
+
+// Next returns encoded next cursor.
+func (p CursorPagination) Next() string {
+	return p.next
+}
+
+// NextURL returns url of the next page or empty string if there is no next
+// cursor.
+func (p CursorPagination) NextURL() string {
+	if p.url == nil || p.next == "" {
+		return ""
+	}
+	uri := *p.url
+	params := uri.Query()
+	params.Set(CursorParam, p.next)
+	params.Set(PerPageParam, strconv.Itoa(p.perPage))
+	uri.RawQuery = params.Encode()
+
+	return uri.String()
+}
+
+// Render renders payload and respond to the client request with next cursor
+// in header.
+func (p CursorPagination) Render(w http.ResponseWriter, r *http.Request, v interface{}, params ...interface{}) {
+	if p.next == "" && CursorFunc != nil {
+		if last, ok := lastItem(v, p.perPage); ok {
+			p.next = CursorFunc(last)
+		}
+	}
+	if p.next != "" {
+		w.Header().Set(NextCursorHeader, p.next)
+		w.Header().Add(LinkHeader, fmt.Sprintf(Linkf, p.NextURL(), "next"))
+	}
+
+	Render(w, r, v, params...)
+}
+
+// lastItem returns last element of slice v if it has at least perPage
+// elements, shorter page means there are no more items.
+func lastItem(v interface{}, perPage int) (interface{}, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	if rv.Len() == 0 || rv.Len() < perPage {
+		return nil, false
+	}
+	return rv.Index(rv.Len() - 1).Interface(), true
+}
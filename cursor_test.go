@@ -0,0 +1,146 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
+)
+
+type keyset struct {
+	ID      int    `json:"id"`
+	Created string `json:"created"`
+}
+
+func TestEncodeDecodeCursor(t *testing.T) {
+	cursor := keyset{
+		ID:      42,
+		Created: "2022-11-01T10:00:00Z",
+	}
+
+	encoded := render.EncodeCursor(cursor)
+
+	got := keyset{}
+	err := render.DecodeCursor(encoded, &got)
+	utest.OK(t, err)
+	utest.Equals(t, cursor, got)
+
+	malformed := "x" + encoded[1:]
+	err = render.DecodeCursor(malformed, &keyset{})
+	utest.Assert(t, errors.Is(err, render.ErrInvalidCursor), "expected ErrInvalidCursor, got %v", err)
+
+	err = render.DecodeCursor(encoded+"!", &keyset{})
+	utest.Assert(t, errors.Is(err, render.ErrInvalidCursor), "expected ErrInvalidCursor, got %v", err)
+}
+
+func TestDecodeCursor_Signed(t *testing.T) {
+	refCursorKey := render.CursorKey
+	render.CursorKey = []byte("secret")
+	defer func() {
+		render.CursorKey = refCursorKey
+	}()
+
+	encoded := render.EncodeCursor(keyset{ID: 42})
+
+	got := keyset{}
+	utest.OK(t, render.DecodeCursor(encoded, &got))
+	utest.Equals(t, keyset{ID: 42}, got)
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "unsigned", cursor: base64.RawURLEncoding.EncodeToString([]byte(`{"id":999}`))},
+		{name: "forged payload", cursor: base64.RawURLEncoding.EncodeToString([]byte(`{"id":999}`)) + encoded[strings.LastIndex(encoded, "."):]},
+		{name: "invalid signature", cursor: encoded + "x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := render.DecodeCursor(tt.cursor, &keyset{})
+			utest.Assert(t, errors.Is(err, render.ErrInvalidCursor), "expected ErrInvalidCursor, got %v", err)
+		})
+	}
+}
+
+func TestCursorPagination_Render(t *testing.T) {
+	first := keyset{ID: 10}
+	r := httptest.NewRequest(http.MethodGet, "http://localhost/users?per_page=2&cursor="+render.EncodeCursor(first), nil)
+	r.Header.Set(render.AcceptHeader, render.ApplicationJSON)
+	w := httptest.NewRecorder()
+
+	pagination := render.CursorPaginationFromRequest(r)
+
+	got := keyset{}
+	err := pagination.Decode(&got)
+	utest.OK(t, err)
+	utest.Equals(t, first, got)
+	utest.Equals(t, 2, pagination.PerPage())
+
+	next := keyset{ID: 12}
+	pagination = pagination.WithNext(next)
+	pagination.Render(w, r, []keyset{{ID: 11}, next})
+
+	nextURL := fmt.Sprintf("http://localhost/users?%s=%s&%s=2", render.CursorParam, render.EncodeCursor(next), render.PerPageParam)
+	utest.Equals(t, render.EncodeCursor(next), w.Header().Get(render.NextCursorHeader))
+	utest.Equals(t, fmt.Sprintf(render.Linkf, nextURL, "next"), w.Header().Get(render.LinkHeader))
+}
+
+func TestCursorFunc(t *testing.T) {
+	render.CursorFunc = func(lastItem interface{}) string {
+		return render.EncodeCursor(lastItem.(keyset).ID)
+	}
+	defer func() {
+		render.CursorFunc = nil
+	}()
+
+	tests := []struct {
+		name  string
+		items []keyset
+		next  string
+	}{
+		{
+			name:  "full page",
+			items: []keyset{{ID: 11}, {ID: 12}},
+			next:  render.EncodeCursor(12),
+		},
+		{
+			name:  "last page",
+			items: []keyset{{ID: 13}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "http://localhost/users?per_page=2", nil)
+			w := httptest.NewRecorder()
+
+			render.CursorPaginationFromRequest(r).Render(w, r, tt.items)
+
+			utest.Equals(t, tt.next, w.Header().Get(render.NextCursorHeader))
+		})
+	}
+}
@@ -20,11 +20,15 @@
 package render
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/ajg/form"
 )
@@ -42,6 +46,15 @@ var (
 	// FormDecoder is a package-level variable set to our default Form decoder
 	// function.
 	FormDecoder = DefaultFormDecoder
+	// DecodeUnknownAsDefault tries to decode request body with unknown content
+	// type using decoder for DefaultContentType.
+	DecodeUnknownAsDefault = false
+	// DecodeAliases enables `aliases` struct tag in JSON decoding, any of
+	// comma separated alias keys is bound to the field.
+	DecodeAliases = false
+	// FormJSONTagFallback makes form decoding use `json` tag names for struct
+	// fields without `form` tag.
+	FormJSONTagFallback = false
 )
 
 // Decoder decodes data from reader
@@ -85,11 +98,17 @@ func DefaultDecoder(r *http.Request, v interface{}) (err error) {
 		err = DecodeXML(r.Body, v)
 	case ContentTypeForm:
 		err = DecodeForm(r.Body, v)
+	case ContentTypeTOML:
+		err = DecodeTOML(r.Body, v)
 	case ContentTypePlainText:
 		// to consider (string for example)
 	case ContentTypeEventStream, ContentTypeHTML:
 		// event stream not used
 	case ContentTypeUnknown: // this should be always on top of default
+		if DecodeUnknownAsDefault {
+			err = decodeDefault(r, v)
+			break
+		}
 		fallthrough
 	default:
 		err = ErrUnableToParseContentType
@@ -97,9 +116,78 @@ func DefaultDecoder(r *http.Request, v interface{}) (err error) {
 	return
 }
 
+// DecodeAll decodes request body, when present, into v and then overlays
+// query params using form decoder. Query params take precedence over body
+// values for the same field, for example with request
+// `POST /users?limit=10` and body `{"name":"Enver","limit":5}`, limit is 10.
+// Query params without matching field are ignored.
+func DecodeAll(r *http.Request, v interface{}) error {
+	if r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0 {
+		if err := Decode(r, v); err != nil {
+			return err
+		}
+	}
+	if r.URL == nil || r.URL.RawQuery == "" {
+		return nil
+	}
+	return decodeForm(strings.NewReader(r.URL.RawQuery), v, true)
+}
+
+// DecodeWith decodes request body into v using decoder created by newDecoder
+// instead of detecting it from request content type, for example:
+//
+//	err := render.DecodeWith(r, &v, func(r io.Reader) render.Decoder {
+//		dec := json.NewDecoder(r)
+//		dec.UseNumber()
+//		return dec
+//	})
+func DecodeWith(r *http.Request, v interface{}, newDecoder func(r io.Reader) Decoder) error {
+	defer io.Copy(io.Discard, r.Body) //nolint:errcheck
+	return newDecoder(r.Body).Decode(v)
+}
+
+// decodeDefault buffers request body and decodes it using decoder for
+// DefaultContentType. Request body is restored so it can be read again.
+func decodeDefault(r *http.Request, v interface{}) error {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+
+	switch DefaultContentType {
+	case ContentTypeJSON:
+		err = DecodeJSON(bytes.NewReader(data), v)
+	case ContentTypeXML:
+		err = DecodeXML(bytes.NewReader(data), v)
+	case ContentTypeForm:
+		err = DecodeForm(bytes.NewReader(data), v)
+	case ContentTypeTOML:
+		err = DecodeTOML(bytes.NewReader(data), v)
+	default:
+		return ErrUnableToParseContentType
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnableToParseContentType, err)
+	}
+	return nil
+}
+
 // DecodeJSON decodes a given reader into an interface using the json decoder.
 func DecodeJSON(r io.Reader, v interface{}) error {
 	defer io.Copy(io.Discard, r) //nolint:errcheck
+	if DecodeAliases {
+		if aliases := fieldAliases(v); len(aliases) > 0 {
+			data, err := io.ReadAll(r)
+			if err != nil {
+				return err
+			}
+			if data, err = resolveAliases(data, aliases); err != nil {
+				return err
+			}
+			r = bytes.NewReader(data)
+		}
+	}
 	return JSONDecoder(r).Decode(v)
 }
 
@@ -111,5 +199,28 @@ func DecodeXML(r io.Reader, v interface{}) error {
 
 // DecodeForm decodes a given reader into an interface using the form decoder.
 func DecodeForm(r io.Reader, v interface{}) error {
-	return FormDecoder(r).Decode(v)
+	return decodeForm(r, v, false)
+}
+
+// decodeForm decodes form data from r into v, keys without matching field
+// are ignored when ignoreUnknown is true and FormDecoder supports it.
+func decodeForm(r io.Reader, v interface{}, ignoreUnknown bool) error {
+	if FormJSONTagFallback {
+		if names := jsonFormNames(v); len(names) > 0 {
+			data, err := io.ReadAll(r)
+			if err != nil {
+				return err
+			}
+			values, err := url.ParseQuery(string(data))
+			if err != nil {
+				return err
+			}
+			r = strings.NewReader(resolveFormNames(values, names).Encode())
+		}
+	}
+	dec := FormDecoder(r)
+	if fd, ok := dec.(*form.Decoder); ok && ignoreUnknown {
+		fd.IgnoreUnknownKeys(true)
+	}
+	return dec.Decode(v)
 }
@@ -20,9 +20,11 @@
 package render_test
 
 import (
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -110,3 +112,147 @@ func TestDefaultDecoder(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeUnknownAsDefault(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+	newRequest := func(body string) *http.Request {
+		return &http.Request{
+			Header: http.Header{},
+			Body:   io.NopCloser(strings.NewReader(body)),
+		}
+	}
+
+	refDecodeUnknownAsDefault := render.DecodeUnknownAsDefault
+	render.DecodeUnknownAsDefault = true
+
+	user := User{}
+	err := render.DefaultDecoder(newRequest(`{"name":"Enver"}`), &user)
+	utest.OK(t, err)
+	utest.Equals(t, "Enver", user.Name)
+
+	err = render.DefaultDecoder(newRequest("name=Enver"), &User{})
+	utest.Assert(t, errors.Is(err, render.ErrUnableToParseContentType), "expected ErrUnableToParseContentType, got %v", err)
+
+	render.DecodeUnknownAsDefault = false
+
+	err = render.DefaultDecoder(newRequest(`{"name":"Enver"}`), &User{})
+	utest.Assert(t, errors.Is(err, render.ErrUnableToParseContentType), "expected ErrUnableToParseContentType, got %v", err)
+
+	render.DecodeUnknownAsDefault = refDecodeUnknownAsDefault
+}
+
+func TestDecodeAll(t *testing.T) {
+	type search struct {
+		Name   string `json:"name" form:"-"`
+		Filter string `json:"filter" form:"filter"`
+		Limit  int    `json:"limit" form:"limit"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/users?filter=active&limit=10&pretty",
+		strings.NewReader(`{"name":"Enver","limit":5}`))
+	r.Header.Set(render.ContentTypeHeader, render.ApplicationJSON)
+
+	v := search{}
+	utest.OK(t, render.DecodeAll(r, &v))
+	utest.Equals(t, search{Name: "Enver", Filter: "active", Limit: 10}, v)
+
+	r = httptest.NewRequest(http.MethodGet, "/users?filter=active", nil)
+
+	v = search{}
+	utest.OK(t, render.DecodeAll(r, &v))
+	utest.Equals(t, search{Filter: "active"}, v)
+}
+
+func TestDecodeWith(t *testing.T) {
+	const body = `{"id":9007199254740993}`
+	newRequest := func() *http.Request {
+		return &http.Request{
+			Header: http.Header{
+				render.ContentTypeHeader: []string{render.ApplicationJSON},
+			},
+			Body: io.NopCloser(strings.NewReader(body)),
+		}
+	}
+
+	v := map[string]interface{}{}
+	err := render.DecodeWith(newRequest(), &v, func(r io.Reader) render.Decoder {
+		dec := json.NewDecoder(r)
+		dec.UseNumber()
+		return dec
+	})
+	utest.OK(t, err)
+	utest.Equals(t, json.Number("9007199254740993"), v["id"])
+
+	v = map[string]interface{}{}
+	utest.OK(t, render.Decode(newRequest(), &v))
+	utest.Equals(t, float64(9007199254740992), v["id"])
+}
+
+func TestDecodeAliases(t *testing.T) {
+	type User struct {
+		FirstName string `json:"first" aliases:"first_name,firstName"`
+	}
+	newRequest := func(body string) *http.Request {
+		return &http.Request{
+			Header: http.Header{
+				render.ContentTypeHeader: []string{render.ApplicationJSON},
+			},
+			Body: io.NopCloser(strings.NewReader(body)),
+		}
+	}
+
+	refDecodeAliases := render.DecodeAliases
+	render.DecodeAliases = true
+
+	tests := []struct {
+		body string
+		want string
+	}{
+		{body: `{"first_name":"x"}`, want: "x"},
+		{body: `{"firstName":"y"}`, want: "y"},
+		{body: `{"first":"z","firstName":"y"}`, want: "z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			user := User{}
+			err := render.DefaultDecoder(newRequest(tt.body), &user)
+			utest.OK(t, err)
+			utest.Equals(t, tt.want, user.FirstName)
+		})
+	}
+
+	render.DecodeAliases = refDecodeAliases
+}
+
+func TestFormJSONTagFallback(t *testing.T) {
+	type User struct {
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name" form:"surname"`
+	}
+	newRequest := func() *http.Request {
+		return &http.Request{
+			Header: http.Header{
+				render.ContentTypeHeader: []string{render.ApplicationFormURL},
+			},
+			Body: io.NopCloser(strings.NewReader("first_name=Enver&surname=Bisevac")),
+		}
+	}
+
+	refFormJSONTagFallback := render.FormJSONTagFallback
+	render.FormJSONTagFallback = true
+
+	user := User{}
+	err := render.DefaultDecoder(newRequest(), &user)
+	utest.OK(t, err)
+	utest.Equals(t, User{FirstName: "Enver", LastName: "Bisevac"}, user)
+
+	render.FormJSONTagFallback = false
+
+	user = User{}
+	_ = render.DefaultDecoder(newRequest(), &user)
+	utest.Equals(t, "", user.FirstName)
+
+	render.FormJSONTagFallback = refFormJSONTagFallback
+}
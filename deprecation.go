@@ -0,0 +1,118 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	// DeprecationWarnings enables `_warnings` array in JSON responses for struct
+	// fields tagged with `render:"deprecated"`. Only fields of top-level
+	// response struct are reported.
+	DeprecationWarnings = false
+	// WarningsKey is the JSON key for deprecation warnings
+	WarningsKey = "_warnings"
+	// DeprecationWarningf is format for deprecation warning entries
+	DeprecationWarningf = "field %q is deprecated"
+)
+
+// deprecatedFields returns JSON names of struct fields tagged as deprecated.
+func deprecatedFields(v interface{}) []string {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []string
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.Tag.Get("render") != "deprecated" {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		fields = append(fields, name)
+	}
+	return fields
+}
+
+// appendWarnings merges deprecation warnings for v into encoded JSON object b.
+// Only fields of top-level struct v are checked, structs nested in fields or
+// in slices get no warnings. Indented documents are indented again after
+// merging.
+func appendWarnings(b []byte, v interface{}) ([]byte, error) {
+	fields := deprecatedFields(v)
+	if len(fields) == 0 {
+		return b, nil
+	}
+
+	trimmed := bytes.TrimSpace(b)
+	if len(trimmed) < 2 || trimmed[len(trimmed)-1] != '}' {
+		return b, nil
+	}
+
+	warnings := make([]string, 0, len(fields))
+	for _, field := range fields {
+		warnings = append(warnings, fmt.Sprintf(DeprecationWarningf, field))
+	}
+
+	key, err := json.Marshal(WarningsKey)
+	if err != nil {
+		return nil, err
+	}
+	value, err := json.Marshal(warnings)
+	if err != nil {
+		return nil, err
+	}
+
+	compact := &bytes.Buffer{}
+	if err := json.Compact(compact, trimmed); err != nil {
+		return nil, err
+	}
+	body := compact.Bytes()[:compact.Len()-1]
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(b)+len(key)+len(value)+2))
+	buf.Write(body)
+	if len(body) > 1 {
+		buf.WriteByte(',')
+	}
+	buf.Write(key)
+	buf.WriteByte(':')
+	buf.Write(value)
+	buf.WriteByte('}')
+	return indentLike(b, buf.Bytes())
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
+)
+
+func TestDeprecationWarnings(t *testing.T) {
+	type user struct {
+		Name     string `json:"name"`
+		Username string `json:"username" render:"deprecated"`
+	}
+
+	r := &http.Request{
+		URL: &url.URL{},
+		Header: http.Header{
+			render.AcceptHeader: []string{render.ApplicationJSON},
+		},
+	}
+	v := user{
+		Name:     "Enver",
+		Username: "enver",
+	}
+
+	refDeprecationWarnings := render.DeprecationWarnings
+	render.DeprecationWarnings = true
+
+	w := httptest.NewRecorder()
+	render.Render(w, r, v)
+	utest.Equals(t, `{"name":"Enver","username":"enver","_warnings":["field \"username\" is deprecated"]}`+"\n", w.Body.String())
+
+	render.DeprecationWarnings = false
+
+	w = httptest.NewRecorder()
+	render.Render(w, r, v)
+	utest.Equals(t, `{"name":"Enver","username":"enver"}`+"\n", w.Body.String())
+
+	render.DeprecationWarnings = refDeprecationWarnings
+}
+
+func TestDeprecationWarnings_Pretty(t *testing.T) {
+	type user struct {
+		Name     string `json:"name"`
+		Username string `json:"username" render:"deprecated"`
+	}
+
+	r := &http.Request{
+		URL: &url.URL{RawQuery: "pretty"},
+		Header: http.Header{
+			render.AcceptHeader: []string{render.ApplicationJSON},
+		},
+	}
+
+	refDeprecationWarnings := render.DeprecationWarnings
+	render.DeprecationWarnings = true
+	defer func() {
+		render.DeprecationWarnings = refDeprecationWarnings
+	}()
+
+	w := httptest.NewRecorder()
+	render.Render(w, r, user{Name: "Enver", Username: "enver"})
+
+	utest.Equals(t, "{\n"+
+		`  "name": "Enver",`+"\n"+
+		`  "username": "enver",`+"\n"+
+		`  "_warnings": [`+"\n"+
+		`    "field \"username\" is deprecated"`+"\n"+
+		"  ]\n"+
+		"}\n", w.Body.String())
+}
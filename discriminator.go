@@ -0,0 +1,81 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// DecodeDiscriminated decodes polymorphic request body. Value of discriminator
+// field is used to pick factory from registry and body is decoded into value
+// returned by factory, for example:
+//
+//	v, err := render.DecodeDiscriminated(r, "type", map[string]func() interface{}{
+//		"card": func() interface{} { return &CardPayment{} },
+//		"bank": func() interface{} { return &BankPayment{} },
+//	})
+//
+// Only JSON and form request bodies are supported. ErrUnknownDiscriminator is
+// returned when field is missing or its value is not registered.
+func DecodeDiscriminated(r *http.Request, field string, registry map[string]func() interface{}) (interface{}, error) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+
+	var discriminator string
+	switch GetRequestContentType(r) {
+	case ContentTypeJSON:
+		object := map[string]json.RawMessage{}
+		if err := json.Unmarshal(data, &object); err != nil {
+			return nil, err
+		}
+		if raw, ok := object[field]; ok {
+			if err := json.Unmarshal(raw, &discriminator); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrUnknownDiscriminator, err)
+			}
+		}
+	case ContentTypeForm:
+		values, err := url.ParseQuery(string(data))
+		if err != nil {
+			return nil, err
+		}
+		discriminator = values.Get(field)
+	default:
+		return nil, ErrUnableToParseContentType
+	}
+
+	factory, ok := registry[discriminator]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s=%q", ErrUnknownDiscriminator, field, discriminator)
+	}
+
+	v := factory()
+	if err := Decode(r, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
+)
+
+type cardPayment struct {
+	Type   string `json:"type"`
+	Number string `json:"number"`
+}
+
+type bankPayment struct {
+	Type string `json:"type"`
+	IBAN string `json:"iban"`
+}
+
+func TestDecodeDiscriminated(t *testing.T) {
+	registry := map[string]func() interface{}{
+		"card": func() interface{} { return &cardPayment{} },
+		"bank": func() interface{} { return &bankPayment{} },
+	}
+	newRequest := func(body string) *http.Request {
+		return &http.Request{
+			Header: http.Header{
+				render.ContentTypeHeader: []string{render.ApplicationJSON},
+			},
+			Body: io.NopCloser(strings.NewReader(body)),
+		}
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want interface{}
+		err  error
+	}{
+		{
+			name: "card payment",
+			body: `{"type":"card","number":"4111"}`,
+			want: &cardPayment{Type: "card", Number: "4111"},
+		},
+		{
+			name: "bank payment",
+			body: `{"type":"bank","iban":"BA39"}`,
+			want: &bankPayment{Type: "bank", IBAN: "BA39"},
+		},
+		{
+			name: "unknown type",
+			body: `{"type":"cash"}`,
+			err:  render.ErrUnknownDiscriminator,
+		},
+		{
+			name: "missing type",
+			body: `{"iban":"BA39"}`,
+			err:  render.ErrUnknownDiscriminator,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := render.DecodeDiscriminated(newRequest(tt.body), "type", registry)
+			utest.Assert(t, errors.Is(err, tt.err), "DecodeDiscriminated() error = %v, wantErr %v", err, tt.err)
+			utest.Equals(t, tt.want, got)
+		})
+	}
+}
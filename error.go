@@ -20,6 +20,7 @@
 package render
 
 import (
+	"context"
 	"errors"
 	"net/http"
 )
@@ -36,16 +37,137 @@ var (
 
 	// ErrNotFound is returned when a resource is not found.
 	ErrNotFound = errors.New("not found")
+
+	// ErrNotAcceptable is returned when none of the accepted content types
+	// can be produced.
+	ErrNotAcceptable = errors.New("not acceptable")
+
+	// ErrServiceUnavailable is returned when service is temporarily unable
+	// to handle the request, for example during shutdown.
+	ErrServiceUnavailable = errors.New("service unavailable")
+
+	// ErrResponseTooLarge is returned when encoded response exceeds
+	// MaxResponseBytes.
+	ErrResponseTooLarge = errors.New("response too large")
+
+	// ErrInvalidCursor is returned when pagination cursor is malformed.
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrUnknownDiscriminator is returned when discriminator value of
+	// polymorphic request body is missing or not registered.
+	ErrUnknownDiscriminator = errors.New("unknown discriminator")
+
+	// ErrTooManyParts is returned when multipart request body has more parts
+	// than MaxMultipartParts.
+	ErrTooManyParts = errors.New("too many multipart parts")
+
+	// ErrPartTooLarge is returned when multipart text part is larger than
+	// MaxMultipartTextBytes.
+	ErrPartTooLarge = errors.New("multipart part too large")
+
+	// ErrIdempotencyConflict is returned when request with the same
+	// idempotency key is still in progress.
+	ErrIdempotencyConflict = errors.New("request with the same idempotency key is in progress")
+
+	// ErrIdempotencyKeyReused is returned when idempotency key is reused for
+	// request with different method, path or body.
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused for different request")
 )
 
 // ErrorMap contains predefined errors with assigned status code.
 var ErrorMap = map[error]int{
-	ErrInvalidToken: http.StatusBadRequest,
-	ErrUnauthorized: http.StatusUnauthorized,
-	ErrForbidden:    http.StatusForbidden,
-	ErrNotFound:     http.StatusNotFound,
+	ErrInvalidToken:         http.StatusBadRequest,
+	ErrUnauthorized:         http.StatusUnauthorized,
+	ErrForbidden:            http.StatusForbidden,
+	ErrNotFound:             http.StatusNotFound,
+	ErrNotAcceptable:        http.StatusNotAcceptable,
+	ErrServiceUnavailable:   http.StatusServiceUnavailable,
+	ErrInvalidCursor:        http.StatusBadRequest,
+	ErrUnknownDiscriminator: http.StatusBadRequest,
+	ErrTooManyParts:         http.StatusRequestEntityTooLarge,
+	ErrPartTooLarge:         http.StatusRequestEntityTooLarge,
+	ErrIdempotencyConflict:  http.StatusConflict,
+	ErrIdempotencyKeyReused: http.StatusUnprocessableEntity,
+}
+
+// ErrorCodes contains application error codes assigned to errors, used to
+// build documentation URL of the error, for example:
+//
+//	render.ErrorDocsBaseURL = "https://docs.example.com/errors/"
+//	render.ErrorCodes[ErrQuotaExceeded] = "quota-exceeded"
+var ErrorCodes = map[error]string{}
+
+// ErrorDocsBaseURL is base URL of errors documentation. When set, error
+// responses of errors with code in ErrorCodes include URL made of
+// ErrorDocsBaseURL followed by the code.
+var ErrorDocsBaseURL = ""
+
+// errorCode returns code of err from ErrorCodes.
+func errorCode(err error) string {
+	for key, code := range ErrorCodes {
+		if errors.Is(err, key) {
+			return code
+		}
+	}
+	return ""
 }
 
+// errorDocsURL returns documentation URL for code or empty string when
+// ErrorDocsBaseURL or code is not set.
+func errorDocsURL(code string) string {
+	if ErrorDocsBaseURL == "" || code == "" {
+		return ""
+	}
+	return ErrorDocsBaseURL + code
+}
+
+// IndentErrors renders JSON error responses with indentation, independent of
+// indentation of successful responses. Errors are still encoded with
+// JSONEncoder, see IndentJSONEncoder.
+var IndentErrors = false
+
+// ErrorClass classifies errors by response status code.
+type ErrorClass int
+
+// Error classes handled by this package.
+const (
+	// ClientError is class of errors with 4xx status codes.
+	ClientError ErrorClass = iota
+	// ServerError is class of errors with 5xx status codes.
+	ServerError
+)
+
+// IsServerError reports whether status is 5xx server error.
+func IsServerError(status int) bool {
+	return status >= http.StatusInternalServerError
+}
+
+// ClassifyStatus returns ErrorClass for status code.
+func ClassifyStatus(status int) ErrorClass {
+	if IsServerError(status) {
+		return ServerError
+	}
+	return ClientError
+}
+
+// OnError is a package-level variable set to function called for every error
+// rendered with Error and when response can't be rendered, for example when it
+// exceeds MaxResponseBytes or client disconnected. Class allows routing client
+// errors and server errors to different log levels, for example:
+//
+//	render.OnError = func(r *http.Request, err error, class render.ErrorClass) {
+//		if class == render.ServerError {
+//			log.Error(err)
+//			return
+//		}
+//		log.Info(err)
+//	}
+//
+// Request passed to the function is never nil, errors of responses rendered
+// directly with JSON, XML and other renderers outside of Render are not
+// reported. Default function does nothing.
+var OnError = func(r *http.Request, err error, class ErrorClass) {}
+
 // TreatError is a package-level variable set to default function with basic
 // error message response. Any error provided will have just a simple struct
 // with field message describing the error. Developer can create custom function
@@ -69,9 +191,32 @@ var ErrorMap = map[error]int{
 // and render.Error(w, r, err) will create response based of your treat function.
 var TreatError = DefaultErrorRespond
 
+// treatErrorCtxKey is context key for request scoped TreatError function.
+type treatErrorCtxKey struct{}
+
+// WithTreatError returns copy of ctx with TreatError override. Error uses it
+// instead of package-level TreatError for requests carrying this context,
+// for example in middleware:
+//
+//	r = r.WithContext(render.WithTreatError(r.Context(), adminTreatError))
+func WithTreatError(ctx context.Context, fn func(r *http.Request, err error) interface{}) context.Context {
+	return context.WithValue(ctx, treatErrorCtxKey{}, fn)
+}
+
+// treatError returns TreatError override from request context or package-level
+// TreatError.
+func treatError(r *http.Request) func(r *http.Request, err error) interface{} {
+	if fn, ok := r.Context().Value(treatErrorCtxKey{}).(func(r *http.Request, err error) interface{}); ok && fn != nil {
+		return fn
+	}
+	return TreatError
+}
+
 // ErrorResponse represents a json-encoded API error.
 type ErrorResponse struct {
 	Message string `json:"message" xml:"message"`
+	Code    string `json:"code,omitempty" xml:"code,omitempty"`
+	Help    string `json:"help,omitempty" xml:"help,omitempty"`
 }
 
 // HTTPError helper structure used as error with status code.
@@ -87,27 +232,25 @@ func (h HTTPError) Error() string {
 
 // DefaultErrorRespond returns ErrorResponse object for later processing
 func DefaultErrorRespond(r *http.Request, err error) interface{} {
+	code := errorCode(err)
 	return ErrorResponse{
 		Message: err.Error(),
+		Code:    code,
+		Help:    errorDocsURL(code),
 	}
 }
 
 // Error renders response body with content type based on Accept header of request.
 // Status codes must be >= 400.
 func Error(w http.ResponseWriter, r *http.Request, err error, params ...interface{}) {
-	status := http.StatusInternalServerError
-	// find in map of default errors and return status
-	for key, value := range ErrorMap {
-		if errors.Is(err, key) {
-			status = value
-		}
+	status, err := errorStatus(err)
+	if s := paramsStatus(params); s != 0 {
+		status = s
 	}
-	// http error checking
-	httpError := &HTTPError{}
-	if errors.As(err, &httpError) {
-		status = httpError.Status
-		err = httpError.Err
+	OnError(r, err, ClassifyStatus(status))
+	v := treatError(r)(r, err)
+	if IndentErrors {
+		params = append(params, IndentJSONEncoder)
 	}
-	v := TreatError(r, err)
 	Respond(w, r, v, append(params, status)...)
 }
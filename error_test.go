@@ -23,8 +23,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"testing"
 
 	"github.com/enverbisevac/render"
@@ -156,3 +159,176 @@ func TestError(t *testing.T) {
 		})
 	}
 }
+
+func TestWithTreatError(t *testing.T) {
+	type adminError struct {
+		Message string `json:"message"`
+		Detail  string `json:"detail"`
+	}
+	newRequest := func() *http.Request {
+		return &http.Request{
+			URL: &url.URL{},
+			Header: http.Header{
+				render.AcceptHeader: []string{render.ApplicationJSON},
+			},
+		}
+	}
+
+	r := newRequest()
+	r = r.WithContext(render.WithTreatError(r.Context(), func(r *http.Request, err error) interface{} {
+		return adminError{
+			Message: "internal error",
+			Detail:  err.Error(),
+		}
+	}))
+	w := httptest.NewRecorder()
+
+	render.Error(w, r, errors.New("db connection lost"))
+
+	utest.Equals(t, http.StatusInternalServerError, w.Code)
+	utest.Equals(t, `{"message":"internal error","detail":"db connection lost"}`+"\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+
+	render.Error(w, newRequest(), errors.New("db connection lost"))
+
+	utest.Equals(t, `{"message":"db connection lost"}`+"\n", w.Body.String())
+}
+
+func TestIsServerError(t *testing.T) {
+	tests := []struct {
+		status int
+		want   bool
+		class  render.ErrorClass
+	}{
+		{status: http.StatusBadRequest, want: false, class: render.ClientError},
+		{status: http.StatusNotFound, want: false, class: render.ClientError},
+		{status: http.StatusTooManyRequests, want: false, class: render.ClientError},
+		{status: http.StatusInternalServerError, want: true, class: render.ServerError},
+		{status: http.StatusServiceUnavailable, want: true, class: render.ServerError},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.status), func(t *testing.T) {
+			utest.Equals(t, tt.want, render.IsServerError(tt.status))
+			utest.Equals(t, tt.class, render.ClassifyStatus(tt.status))
+		})
+	}
+}
+
+func TestError_OnError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		params []interface{}
+		class  render.ErrorClass
+	}{
+		{
+			name:  "mapped client error",
+			err:   render.ErrNotFound,
+			class: render.ClientError,
+		},
+		{
+			name:  "default server error",
+			err:   errors.New("db connection lost"),
+			class: render.ServerError,
+		},
+		{
+			name:   "status param overrides classification",
+			err:    errors.New("bad input"),
+			params: []interface{}{http.StatusBadRequest},
+			class:  render.ClientError,
+		},
+	}
+
+	var (
+		onErr   error
+		onClass render.ErrorClass
+	)
+	refOnError := render.OnError
+	render.OnError = func(r *http.Request, err error, class render.ErrorClass) {
+		onErr = err
+		onClass = class
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			w := httptest.NewRecorder()
+
+			render.Error(w, r, tt.err, tt.params...)
+
+			utest.Equals(t, tt.err, onErr)
+			utest.Equals(t, tt.class, onClass)
+		})
+	}
+	render.OnError = refOnError
+}
+
+func TestIndentErrors(t *testing.T) {
+	refIndentErrors := render.IndentErrors
+	render.IndentErrors = true
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(render.AcceptHeader, render.ApplicationJSON)
+
+	w := httptest.NewRecorder()
+	render.Error(w, r, render.ErrNotFound)
+	utest.Equals(t, "{\n  \"message\": \"not found\"\n}\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	render.Render(w, r, render.ErrorResponse{Message: "found"})
+	utest.Equals(t, `{"message":"found"}`+"\n", w.Body.String())
+
+	render.IndentErrors = refIndentErrors
+}
+
+func TestIndentErrors_JSONEncoder(t *testing.T) {
+	refIndentErrors, refJSONEncoder := render.IndentErrors, render.JSONEncoder
+	render.IndentErrors = true
+	render.JSONEncoder = func(w io.Writer) render.Encoder {
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return enc
+	}
+	defer func() {
+		render.IndentErrors, render.JSONEncoder = refIndentErrors, refJSONEncoder
+	}()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(render.AcceptHeader, render.ApplicationJSON)
+	w := httptest.NewRecorder()
+
+	render.Error(w, r, errors.New("<b> not allowed"), http.StatusBadRequest)
+
+	utest.Equals(t, "{\n  \"message\": \"<b> not allowed\"\n}\n", w.Body.String())
+}
+
+func TestErrorDocsBaseURL(t *testing.T) {
+	errQuota := errors.New("quota exceeded")
+	refErrorDocsBaseURL := render.ErrorDocsBaseURL
+	render.ErrorDocsBaseURL = "https://docs.example.com/errors/"
+	render.ErrorCodes[errQuota] = "quota-exceeded"
+	render.ErrorMap[errQuota] = http.StatusTooManyRequests
+	defer func() {
+		render.ErrorDocsBaseURL = refErrorDocsBaseURL
+		delete(render.ErrorCodes, errQuota)
+		delete(render.ErrorMap, errQuota)
+	}()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(render.AcceptHeader, render.ApplicationJSON)
+
+	w := httptest.NewRecorder()
+	render.Error(w, r, fmt.Errorf("user 1: %w", errQuota))
+	utest.Equals(t, http.StatusTooManyRequests, w.Code)
+	utest.Equals(t, `{"message":"user 1: quota exceeded","code":"quota-exceeded",`+
+		`"help":"https://docs.example.com/errors/quota-exceeded"}`+"\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	render.Problem(w, r, errQuota)
+	utest.Equals(t, `{"type":"https://docs.example.com/errors/quota-exceeded","title":"Too Many Requests",`+
+		`"status":429,"detail":"quota exceeded"}`+"\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	render.Error(w, r, render.ErrNotFound)
+	utest.Equals(t, `{"message":"not found"}`+"\n", w.Body.String())
+}
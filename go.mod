@@ -3,6 +3,7 @@ module github.com/enverbisevac/render
 go 1.16
 
 require (
+	github.com/BurntSushi/toml v1.2.1
 	github.com/ajg/form v1.5.1
 	golang.org/x/text v0.4.0
 )
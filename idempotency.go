@@ -0,0 +1,239 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// IdempotencyKeyHeader represents Idempotency-Key key in header
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyStoreTimeout limits time IdempotencyMiddleware spends storing
+// response and unlocking key. Both use context detached from the request, so
+// they complete even when client disconnected.
+var IdempotencyStoreTimeout = 5 * time.Second
+
+// idempotencySkipHeaders are response headers which are not stored, as they
+// are specific to the connection or to the client session.
+var idempotencySkipHeaders = []string{
+	IdempotencyKeyHeader,
+	"Set-Cookie",
+	"Connection",
+	"Keep-Alive",
+	"Transfer-Encoding",
+	"Trailer",
+	"Upgrade",
+}
+
+// StoredResponse is response recorded for idempotency key. Fingerprint
+// identifies request the response was recorded for.
+type StoredResponse struct {
+	Fingerprint string
+	Status      int
+	Header      http.Header
+	Body        []byte
+}
+
+// IdempotencyStore stores responses by idempotency key. Get returns nil
+// response when nothing is stored under the key. Lock marks key as in-flight
+// and returns false when it already is, Unlock releases it.
+type IdempotencyStore interface {
+	Get(ctx context.Context, key string) (*StoredResponse, error)
+	Set(ctx context.Context, key string, resp StoredResponse) error
+	Lock(ctx context.Context, key string) (bool, error)
+	Unlock(ctx context.Context, key string) error
+}
+
+// IdempotencyKey returns Idempotency-Key header value of the request.
+func IdempotencyKey(r *http.Request) string {
+	return strings.TrimSpace(r.Header.Get(IdempotencyKeyHeader))
+}
+
+// requestFingerprint returns hash of request method, path and body. Request
+// body is restored so it can be read again.
+func requestFingerprint(r *http.Request) (string, error) {
+	var body []byte
+	if r.Body != nil {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			return "", err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(data))
+		body = data
+	}
+	bodyHash := sha256.Sum256(body)
+	hash := sha256.Sum256([]byte(r.Method + " " + r.URL.Path + " " + hex.EncodeToString(bodyHash[:])))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// Replay writes response stored under request idempotency key and returns
+// true, Idempotency-Key header is echoed. When stored response was recorded
+// for request with different method, path or body ErrIdempotencyKeyReused
+// error is rendered instead. False is returned when request has no key or
+// nothing is stored under it. Store errors are passed to OnError and treated
+// as missing response.
+func Replay(w http.ResponseWriter, r *http.Request, store IdempotencyStore) bool {
+	key := IdempotencyKey(r)
+	if key == "" {
+		return false
+	}
+	fingerprint, err := requestFingerprint(r)
+	if err != nil {
+		OnError(r, err, ServerError)
+		return false
+	}
+	return replay(w, r, store, key, fingerprint)
+}
+
+// replay writes response stored under key, see Replay.
+func replay(w http.ResponseWriter, r *http.Request, store IdempotencyStore, key, fingerprint string) bool {
+	resp, err := store.Get(r.Context(), key)
+	if err != nil {
+		OnError(r, err, ServerError)
+		return false
+	}
+	if resp == nil {
+		return false
+	}
+
+	w.Header().Set(IdempotencyKeyHeader, key)
+	if resp.Fingerprint != fingerprint {
+		Error(w, r, ErrIdempotencyKeyReused)
+		return true
+	}
+
+	for name, values := range resp.Header {
+		w.Header()[name] = append([]string(nil), values...)
+	}
+	w.WriteHeader(resp.Status)
+	w.Write(resp.Body) //nolint:errcheck
+	return true
+}
+
+// idempotencyRecorder records response written through it.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader records status code and sends response header.
+func (rec *idempotencyRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+// Write records body and writes it to the response.
+func (rec *idempotencyRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// Flush sends any buffered data to the client if underlying writer supports
+// it.
+func (rec *idempotencyRecorder) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// IdempotencyMiddleware returns middleware which replays stored responses for
+// requests with Idempotency-Key header. Responses of requests with a key not
+// seen before are stored, except server errors so failed requests can be
+// retried. Request with the key of a request still in progress is rejected
+// with ErrIdempotencyConflict, request reusing the key with different method,
+// path or body with ErrIdempotencyKeyReused. Set-Cookie and hop-by-hop headers
+// are not stored. Stored responses are replayed to any client sending the
+// same key, so keys should be scoped per caller, for example by prefixing
+// them in the store with authenticated user ID.
+func IdempotencyMiddleware(store IdempotencyStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := IdempotencyKey(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			fingerprint, err := requestFingerprint(r)
+			if err != nil {
+				Error(w, r, err)
+				return
+			}
+
+			locked, err := store.Lock(r.Context(), key)
+			if err != nil {
+				Error(w, r, err)
+				return
+			}
+			if !locked {
+				w.Header().Set(IdempotencyKeyHeader, key)
+				Error(w, r, ErrIdempotencyConflict)
+				return
+			}
+			defer func() {
+				ctx, cancel := context.WithTimeout(context.Background(), IdempotencyStoreTimeout)
+				defer cancel()
+				if err := store.Unlock(ctx, key); err != nil {
+					OnError(r, err, ServerError)
+				}
+			}()
+
+			if replay(w, r, store, key, fingerprint) {
+				return
+			}
+
+			w.Header().Set(IdempotencyKeyHeader, key)
+			rec := &idempotencyRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+			if rec.status == 0 || IsServerError(rec.status) {
+				return
+			}
+
+			header := w.Header().Clone()
+			for _, name := range idempotencySkipHeaders {
+				header.Del(name)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), IdempotencyStoreTimeout)
+			defer cancel()
+			err = store.Set(ctx, key, StoredResponse{
+				Fingerprint: fingerprint,
+				Status:      rec.status,
+				Header:      header,
+				Body:        rec.body.Bytes(),
+			})
+			if err != nil {
+				OnError(r, err, ServerError)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
+)
+
+type memoryStore struct {
+	mu        sync.Mutex
+	responses map[string]render.StoredResponse
+	inFlight  map[string]bool
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		responses: map[string]render.StoredResponse{},
+		inFlight:  map[string]bool{},
+	}
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) (*render.StoredResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp, ok := s.responses[key]
+	if !ok {
+		return nil, nil
+	}
+	return &resp, nil
+}
+
+func (s *memoryStore) Set(ctx context.Context, key string, resp render.StoredResponse) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[key] = resp
+	return nil
+}
+
+func (s *memoryStore) Lock(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.inFlight[key] {
+		return false, nil
+	}
+	s.inFlight[key] = true
+	return true, nil
+}
+
+func (s *memoryStore) Unlock(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inFlight, key)
+	return nil
+}
+
+func idempotentRequest(method, target, key, body string) *http.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set(render.AcceptHeader, render.ApplicationJSON)
+	r.Header.Set(render.IdempotencyKeyHeader, key)
+	return r
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	store := newMemoryStore()
+	calls := 0
+	handler := render.IdempotencyMiddleware(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		render.Render(w, r, map[string]int{"id": calls}, http.StatusCreated)
+	}))
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, idempotentRequest(http.MethodPost, "/orders", "key-1", `{"item":1}`))
+
+		utest.Equals(t, http.StatusCreated, w.Code)
+		utest.Equals(t, render.ApplicationJSONExt, w.Header().Get(render.ContentTypeHeader))
+		utest.Equals(t, "key-1", w.Header().Get(render.IdempotencyKeyHeader))
+		utest.Equals(t, `{"id":1}`+"\n", w.Body.String())
+	}
+	utest.Equals(t, 1, calls)
+
+	r := idempotentRequest(http.MethodPost, "/orders", "key-1", `{"item":1}`)
+	utest.Equals(t, "key-1", render.IdempotencyKey(r))
+	w := httptest.NewRecorder()
+	utest.Assert(t, render.Replay(w, r, store), "expected stored response to be replayed")
+	utest.Equals(t, `{"id":1}`+"\n", w.Body.String())
+
+	r = idempotentRequest(http.MethodPost, "/orders", "key-2", `{"item":1}`)
+	utest.Assert(t, !render.Replay(httptest.NewRecorder(), r, store), "expected no stored response")
+}
+
+func TestIdempotencyMiddleware_KeyReused(t *testing.T) {
+	handler := render.IdempotencyMiddleware(newMemoryStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		render.Render(w, r, map[string]int{"id": 1}, http.StatusCreated)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, idempotentRequest(http.MethodPost, "/orders", "key-1", `{"item":1}`))
+	utest.Equals(t, http.StatusCreated, w.Code)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+	}{
+		{name: "different body", method: http.MethodPost, target: "/orders", body: `{"item":2}`},
+		{name: "different path", method: http.MethodPost, target: "/invoices", body: `{"item":1}`},
+		{name: "different method", method: http.MethodPut, target: "/orders", body: `{"item":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, idempotentRequest(tt.method, tt.target, "key-1", tt.body))
+
+			utest.Equals(t, http.StatusUnprocessableEntity, w.Code)
+			utest.Equals(t, `{"message":"idempotency key reused for different request"}`+"\n", w.Body.String())
+		})
+	}
+}
+
+func TestIdempotencyMiddleware_Conflict(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	handler := render.IdempotencyMiddleware(newMemoryStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		render.Render(w, r, map[string]int{"id": 1}, http.StatusCreated)
+	}))
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(first, idempotentRequest(http.MethodPost, "/orders", "key-1", `{"item":1}`))
+	}()
+	<-started
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, idempotentRequest(http.MethodPost, "/orders", "key-1", `{"item":1}`))
+	utest.Equals(t, http.StatusConflict, w.Code)
+
+	close(release)
+	<-done
+	utest.Equals(t, http.StatusCreated, first.Code)
+}
+
+func TestIdempotencyMiddleware_ClientDisconnected(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	handler := render.IdempotencyMiddleware(newMemoryStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Set-Cookie", "session=secret")
+		render.Render(w, r, map[string]int{"id": calls}, http.StatusCreated)
+		// client disconnects after response is written
+		cancel()
+	}))
+
+	r := idempotentRequest(http.MethodPost, "/orders", "key-1", `{"item":1}`)
+	handler.ServeHTTP(httptest.NewRecorder(), r.WithContext(ctx))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, idempotentRequest(http.MethodPost, "/orders", "key-1", `{"item":1}`))
+
+	utest.Equals(t, 1, calls)
+	utest.Equals(t, http.StatusCreated, w.Code)
+	utest.Equals(t, `{"id":1}`+"\n", w.Body.String())
+	utest.Equals(t, "", w.Header().Get("Set-Cookie"))
+}
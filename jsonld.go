@@ -0,0 +1,92 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+)
+
+// JSONLDContext is a package-level variable set to function returning
+// `@context` value injected into JSON-LD responses. Nothing is injected when
+// it is nil or returns nil, for example:
+//
+//	render.JSONLDContext = func(r *http.Request, v interface{}) interface{} {
+//		return "https://schema.org"
+//	}
+var JSONLDContext func(r *http.Request, v interface{}) interface{}
+
+// JSONLD marshals 'v' to JSON with `@context` from JSONLDContext, setting the
+// Content-Type as application/ld+json.
+func JSONLD(w http.ResponseWriter, r *http.Request, v interface{}, params ...interface{}) {
+	if requestCanceled(w) {
+		return
+	}
+	b, err := encodeJSON(v, params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if JSONLDContext != nil {
+		if ctx := JSONLDContext(r, v); ctx != nil {
+			if b, err = prependJSONContext(b, ctx); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+	if requestCanceled(w) || exceedsMaxResponse(w, b) {
+		return
+	}
+	Blob(w, b, append(params, ContentTypeHeader, ApplicationLDJSON+"; charset=utf-8")...)
+}
+
+// acceptsJSONLD reports whether application/ld+json is preferred JSON variant
+// in request Accept header.
+func acceptsJSONLD(r *http.Request) bool {
+	mr, ok := acceptedJSON(r)
+	return ok && mr.value == ApplicationLDJSON
+}
+
+// prependJSONContext inserts `@context` key as first member of encoded JSON
+// object b.
+func prependJSONContext(b []byte, ctx interface{}) ([]byte, error) {
+	trimmed := bytes.TrimSpace(b)
+	if len(trimmed) < 2 || trimmed[0] != '{' {
+		return b, nil
+	}
+
+	value, err := json.Marshal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body := bytes.TrimSpace(trimmed[1:])
+	buf := bytes.NewBuffer(make([]byte, 0, len(b)+len(value)+13))
+	buf.WriteString(`{"@context":`)
+	buf.Write(value)
+	if len(body) > 1 {
+		buf.WriteByte(',')
+	}
+	buf.Write(body)
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
+)
+
+func TestJSONLD(t *testing.T) {
+	type person struct {
+		Type string `json:"@type"`
+		Name string `json:"name"`
+	}
+
+	refJSONLDContext := render.JSONLDContext
+	render.JSONLDContext = func(r *http.Request, v interface{}) interface{} {
+		return "https://schema.org"
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/people/1", nil)
+	r.Header.Set(render.AcceptHeader, render.ApplicationLDJSON)
+	w := httptest.NewRecorder()
+
+	render.Render(w, r, person{Type: "Person", Name: "Enver"})
+
+	utest.Equals(t, http.StatusOK, w.Code)
+	utest.Equals(t, "application/ld+json; charset=utf-8", w.Header().Get(render.ContentTypeHeader))
+	utest.Equals(t, `{"@context":"https://schema.org","@type":"Person","name":"Enver"}`+"\n", w.Body.String())
+
+	r.Header.Set(render.AcceptHeader, render.ApplicationJSON)
+	w = httptest.NewRecorder()
+
+	render.Render(w, r, person{Type: "Person", Name: "Enver"})
+
+	utest.Equals(t, render.ApplicationJSONExt, w.Header().Get(render.ContentTypeHeader))
+	utest.Equals(t, `{"@type":"Person","name":"Enver"}`+"\n", w.Body.String())
+
+	render.JSONLDContext = refJSONLDContext
+}
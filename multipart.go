@@ -0,0 +1,108 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// MaxMultipartParts limits number of parts read by DecodeMultipartStream,
+// ErrTooManyParts is returned when request body has more parts. Zero means no
+// limit.
+var MaxMultipartParts = 0
+
+// MaxMultipartTextBytes limits size of single text part read by
+// DecodeMultipartStream, ErrPartTooLarge is returned when text part is
+// larger. Zero means no limit.
+var MaxMultipartTextBytes = 1 << 20
+
+// DecodeMultipartStream reads multipart/form-data request body part by part
+// without buffering it. Text parts are bound to textTarget using form
+// decoder, file parts are handed to onFile as they are read, for example:
+//
+//	err := render.DecodeMultipartStream(r, &meta, func(part *multipart.Part) error {
+//		_, err := io.Copy(storage, part)
+//		return err
+//	})
+//
+// onFile must consume the part before returning, it is not valid after. File
+// parts are skipped when onFile is nil.
+func DecodeMultipartStream(r *http.Request, textTarget interface{}, onFile func(part *multipart.Part) error) error {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return err
+	}
+
+	values := url.Values{}
+	for parts := 1; ; parts++ {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if MaxMultipartParts > 0 && parts > MaxMultipartParts {
+			part.Close()
+			return fmt.Errorf("%w: limit is %d", ErrTooManyParts, MaxMultipartParts)
+		}
+
+		if part.FileName() != "" {
+			if onFile != nil {
+				err = onFile(part)
+			}
+		} else {
+			var data []byte
+			if data, err = readTextPart(part); err == nil {
+				values.Add(part.FormName(), string(data))
+			}
+		}
+		part.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	if textTarget == nil {
+		return nil
+	}
+	return DecodeForm(strings.NewReader(values.Encode()), textTarget)
+}
+
+// readTextPart reads text part up to MaxMultipartTextBytes.
+func readTextPart(part *multipart.Part) ([]byte, error) {
+	if MaxMultipartTextBytes <= 0 {
+		return io.ReadAll(part)
+	}
+	data, err := io.ReadAll(io.LimitReader(part, int64(MaxMultipartTextBytes)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxMultipartTextBytes {
+		return nil, fmt.Errorf("%w: part %q exceeds limit of %d bytes", ErrPartTooLarge, part.FormName(), MaxMultipartTextBytes)
+	}
+	return data, nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
+)
+
+func TestDecodeMultipartStream(t *testing.T) {
+	type upload struct {
+		Title string `form:"title"`
+		Tags  string `form:"tags"`
+	}
+
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	utest.OK(t, mw.WriteField("title", "Report"))
+	fw, err := mw.CreateFormFile("file", "report.csv")
+	utest.OK(t, err)
+	_, err = fw.Write([]byte("name\nEnver\n"))
+	utest.OK(t, err)
+	utest.OK(t, mw.WriteField("tags", "q4"))
+	utest.OK(t, mw.Close())
+
+	r := httptest.NewRequest(http.MethodPost, "/upload", body)
+	r.Header.Set(render.ContentTypeHeader, mw.FormDataContentType())
+
+	var (
+		files    []string
+		contents []string
+	)
+	v := upload{}
+	err = render.DecodeMultipartStream(r, &v, func(part *multipart.Part) error {
+		data, err := io.ReadAll(part)
+		files = append(files, part.FormName()+":"+part.FileName())
+		contents = append(contents, string(data))
+		return err
+	})
+	utest.OK(t, err)
+
+	utest.Equals(t, upload{Title: "Report", Tags: "q4"}, v)
+	utest.Equals(t, []string{"file:report.csv"}, files)
+	utest.Equals(t, []string{"name\nEnver\n"}, contents)
+}
+
+func TestDecodeMultipartStream_NotMultipart(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/upload", bytes.NewBufferString("{}"))
+	r.Header.Set(render.ContentTypeHeader, render.ApplicationJSON)
+
+	err := render.DecodeMultipartStream(r, nil, nil)
+	utest.Equals(t, http.ErrNotMultipart, err)
+}
+
+func TestDecodeMultipartStream_MaxMultipartParts(t *testing.T) {
+	refMaxMultipartParts := render.MaxMultipartParts
+	render.MaxMultipartParts = 2
+	defer func() {
+		render.MaxMultipartParts = refMaxMultipartParts
+	}()
+
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	for _, name := range []string{"a", "b", "c"} {
+		utest.OK(t, mw.WriteField(name, name))
+	}
+	utest.OK(t, mw.Close())
+
+	r := httptest.NewRequest(http.MethodPost, "/upload", body)
+	r.Header.Set(render.ContentTypeHeader, mw.FormDataContentType())
+
+	err := render.DecodeMultipartStream(r, &struct{}{}, func(part *multipart.Part) error {
+		return nil
+	})
+	utest.Assert(t, errors.Is(err, render.ErrTooManyParts), "expected ErrTooManyParts, got %v", err)
+}
+
+func TestDecodeMultipartStream_NilOnFile(t *testing.T) {
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	fw, err := mw.CreateFormFile("file", "report.csv")
+	utest.OK(t, err)
+	_, err = fw.Write([]byte("name\nEnver\n"))
+	utest.OK(t, err)
+	utest.OK(t, mw.WriteField("title", "Report"))
+	utest.OK(t, mw.Close())
+
+	r := httptest.NewRequest(http.MethodPost, "/upload", body)
+	r.Header.Set(render.ContentTypeHeader, mw.FormDataContentType())
+
+	v := struct {
+		Title string `form:"title"`
+	}{}
+	utest.OK(t, render.DecodeMultipartStream(r, &v, nil))
+	utest.Equals(t, "Report", v.Title)
+}
+
+func TestDecodeMultipartStream_MaxMultipartTextBytes(t *testing.T) {
+	refMaxMultipartTextBytes := render.MaxMultipartTextBytes
+	render.MaxMultipartTextBytes = 4
+	defer func() {
+		render.MaxMultipartTextBytes = refMaxMultipartTextBytes
+	}()
+
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	utest.OK(t, mw.WriteField("title", "Report"))
+	utest.OK(t, mw.Close())
+
+	r := httptest.NewRequest(http.MethodPost, "/upload", body)
+	r.Header.Set(render.ContentTypeHeader, mw.FormDataContentType())
+
+	err := render.DecodeMultipartStream(r, &struct{}{}, nil)
+	utest.Assert(t, errors.Is(err, render.ErrPartTooLarge), "expected ErrPartTooLarge, got %v", err)
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+)
+
+// StatusResponse is single sub-response of MultiStatus with its own status
+// code and body. In XML status is rendered as WebDAV status line, for example
+// `HTTP/1.1 404 Not Found`.
+type StatusResponse struct {
+	XMLName xml.Name    `json:"-" xml:"response"`
+	Href    string      `json:"href,omitempty" xml:"href,omitempty"`
+	Status  int         `json:"status" xml:"status"`
+	Body    interface{} `json:"body,omitempty" xml:"body,omitempty"`
+}
+
+// statusResponseXML is XML representation of StatusResponse.
+type statusResponseXML struct {
+	Href   string      `xml:"href,omitempty"`
+	Status string      `xml:"status"`
+	Body   interface{} `xml:"body,omitempty"`
+}
+
+// MarshalXML encodes sub-response with status code as status line.
+func (s StatusResponse) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = xml.Name{Local: "response"}
+	return e.EncodeElement(statusResponseXML{
+		Href:   s.Href,
+		Status: fmt.Sprintf("HTTP/1.1 %d %s", s.Status, http.StatusText(s.Status)),
+		Body:   s.Body,
+	}, start)
+}
+
+// MultiStatus holds sub-responses of batch operation rendered with
+// 207 Multi-Status status code, in XML as WebDAV multistatus element in DAV:
+// namespace, for example:
+//
+//	ms := render.MultiStatus{}
+//	ms.Add("/users/1", http.StatusOK, user)
+//	ms.Add("/users/2", http.StatusNotFound, render.DefaultErrorRespond(r, render.ErrNotFound))
+//	ms.Render(w, r)
+type MultiStatus struct {
+	XMLName   xml.Name         `json:"-" xml:"DAV: multistatus"`
+	Responses []StatusResponse `json:"responses" xml:"response"`
+}
+
+// Add appends sub-response for resource href with status and body.
+func (m *MultiStatus) Add(href string, status int, body interface{}) {
+	m.Responses = append(m.Responses, StatusResponse{
+		Href:   href,
+		Status: status,
+		Body:   body,
+	})
+}
+
+// Render renders sub-responses with 207 Multi-Status status code.
+func (m MultiStatus) Render(w http.ResponseWriter, r *http.Request, params ...interface{}) {
+	Respond(w, r, m, append([]interface{}{http.StatusMultiStatus}, params...)...)
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
+)
+
+func TestMultiStatus_Render(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		body   string
+	}{
+		{
+			name:   "json",
+			accept: render.ApplicationJSON,
+			body: `{"responses":[{"href":"/users/1","status":200,"body":{"name":"Enver"}},` +
+				`{"href":"/users/2","status":404,"body":{"message":"not found"}}]}` + "\n",
+		},
+		{
+			name:   "xml",
+			accept: render.ApplicationXML,
+			body: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<multistatus xmlns="DAV:"><response><href>/users/1</href><status>HTTP/1.1 200 OK</status><body><name>Enver</name></body></response>` +
+				`<response><href>/users/2</href><status>HTTP/1.1 404 Not Found</status><body><message>not found</message></body></response></multistatus>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPatch, "/users", nil)
+			r.Header.Set(render.AcceptHeader, tt.accept)
+
+			ms := render.MultiStatus{}
+			ms.Add("/users/1", http.StatusOK, struct {
+				Name string `json:"name" xml:"name"`
+			}{"Enver"})
+			ms.Add("/users/2", http.StatusNotFound, render.DefaultErrorRespond(r, render.ErrNotFound))
+			ms.Render(w, r)
+
+			utest.Equals(t, http.StatusMultiStatus, w.Code)
+			utest.Equals(t, tt.body, w.Body.String())
+		})
+	}
+}
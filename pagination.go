@@ -20,9 +20,12 @@
 package render
 
 import (
+	"bytes"
+	"encoding/xml"
 	"fmt"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strconv"
 )
 
@@ -52,10 +55,19 @@ var (
 
 	// PaginationInHeader write pagination in header
 	PaginationInHeader = true
+	// PaginationInHeaderAndBody write pagination in both header and body,
+	// regardless of PaginationInHeader value
+	PaginationInHeaderAndBody = false
 	// PaginationHeader generates pagination in header
 	PaginationHeader = DefaultPaginationHeader
 	// PaginationBody generates pagination in body
 	PaginationBody = DefaultPaginationBody
+	// PaginationItemsKey is name of the payload key in pagination body
+	PaginationItemsKey = "items"
+	// OmitPaginationWhenSinglePage skips navigation headers and links in
+	// DefaultPaginationHeader when there is only one page, total headers are
+	// still written
+	OmitPaginationWhenSinglePage = false
 )
 
 // Pagination holds all page related data.
@@ -65,6 +77,24 @@ type Pagination struct {
 	perPage int
 	last    int
 	total   int
+	// count is total items counter used by lazy pagination, nil when
+	// total is known upfront.
+	count *lazyCount
+}
+
+// lazyCount calls count function once, on first access to total.
+type lazyCount struct {
+	fn     func() int
+	called bool
+	total  int
+}
+
+func (c *lazyCount) get() int {
+	if !c.called {
+		c.total = c.fn()
+		c.called = true
+	}
+	return c.total
 }
 
 // PaginationOption is prototype for functional options.
@@ -115,6 +145,56 @@ func NewPagination(url *url.URL, totalItems int, options ...PaginationOption) Pa
 	return pagination
 }
 
+// NewPaginationLazy parses url and returns new pagination object whose total
+// number of items is obtained by calling countFn. countFn is called at most
+// once, only when total dependent values like Last, LastURL or Total are
+// accessed or pagination is rendered. Until then Next and NextURL assume there
+// is a next page. Render doesn't call countFn when rendered slice has fewer
+// items than per page, total is computed from its length then.
+func NewPaginationLazy(url *url.URL, countFn func() int, options ...PaginationOption) Pagination {
+	pagination := NewPagination(url, 0, options...)
+	pagination.count = &lazyCount{fn: countFn}
+	return pagination
+}
+
+// resolved returns pagination with total and last page computed from count
+// function of lazy pagination.
+func (p Pagination) resolved() Pagination {
+	if p.count != nil {
+		p.total = p.count.get()
+		p.last = totalPages(p.perPage, p.total)
+		p.count = nil
+	}
+	return p
+}
+
+// lastPage returns lazy pagination with total computed from length of page
+// items v when v is a slice with fewer items than per page, so count function
+// doesn't have to be called. Empty pages after the first one are not treated
+// as last, as page may be past the real last page.
+func (p Pagination) lastPage(v interface{}) Pagination {
+	if p.counted() || p.page < 1 || p.perPage < 1 {
+		return p
+	}
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return p
+	}
+	n := rv.Len()
+	if n >= p.perPage || (n == 0 && p.page > 1) {
+		return p
+	}
+	p.total = (p.page-1)*p.perPage + n
+	p.last = totalPages(p.perPage, p.total)
+	p.count = nil
+	return p
+}
+
+// counted reports whether total number of items is known.
+func (p Pagination) counted() bool {
+	return p.count == nil || p.count.called
+}
+
 // URL returns non exported page value
 func (p Pagination) URL() *url.URL {
 	return p.url
@@ -155,6 +235,10 @@ func (p Pagination) PrevURL() string {
 
 // Next page
 func (p Pagination) Next() int {
+	if !p.counted() {
+		return p.page + 1
+	}
+	p = p.resolved()
 	return min(p.page+1, p.last)
 }
 
@@ -167,7 +251,7 @@ func (p Pagination) NextURL() string {
 	params.Set(PageParam, strconv.Itoa(p.page))
 	params.Set(PerPageParam, strconv.Itoa(p.perPage))
 
-	if p.page != p.last {
+	if !p.counted() || p.page != p.resolved().last {
 		params.Set(PageParam, strconv.Itoa(p.Next()))
 		p.url.RawQuery = params.Encode()
 
@@ -178,7 +262,7 @@ func (p Pagination) NextURL() string {
 
 // Last page
 func (p Pagination) Last() int {
-	return p.last
+	return p.resolved().last
 }
 
 // LastURL page
@@ -190,7 +274,7 @@ func (p Pagination) LastURL() string {
 	params.Set(PageParam, strconv.Itoa(p.page))
 	params.Set(PerPageParam, strconv.Itoa(p.perPage))
 
-	params.Set(PageParam, strconv.Itoa(p.last))
+	params.Set(PageParam, strconv.Itoa(p.Last()))
 	p.url.RawQuery = params.Encode()
 
 	return p.url.String()
@@ -198,11 +282,11 @@ func (p Pagination) LastURL() string {
 
 // Total returns total number of elements
 func (p Pagination) Total() int {
-	return p.total
+	return p.resolved().total
 }
 
 func (p Pagination) shouldRedirect() bool {
-	last := p.last
+	last := p.Last()
 	switch {
 	case p.page == 0:
 		return true
@@ -217,7 +301,7 @@ func (p Pagination) shouldRedirect() bool {
 func (p Pagination) redirect(w http.ResponseWriter, r *http.Request) {
 	uri := *r.URL
 
-	last := p.last
+	last := p.Last()
 	page := p.page
 	perPage := p.perPage
 
@@ -243,14 +327,16 @@ func (p Pagination) redirect(w http.ResponseWriter, r *http.Request) {
 
 // Render renders payload and respond to the client request.
 func (p Pagination) Render(w http.ResponseWriter, r *http.Request, v interface{}, params ...interface{}) {
+	p = p.lastPage(v)
 	if p.shouldRedirect() {
 		p.redirect(w, r)
 		return
 	}
 
-	if PaginationInHeader {
+	if PaginationInHeader || PaginationInHeaderAndBody {
 		PaginationHeader(w, p)
-	} else {
+	}
+	if !PaginationInHeader || PaginationInHeaderAndBody {
 		v = PaginationBody(p, v)
 	}
 
@@ -259,11 +345,18 @@ func (p Pagination) Render(w http.ResponseWriter, r *http.Request, v interface{}
 
 // DefaultPaginationHeader returns pagination metadata in header.
 func DefaultPaginationHeader(w http.ResponseWriter, p Pagination) {
+	p = p.resolved()
 	w.Header().Set(PageHeader, strconv.Itoa(p.page))
 	w.Header().Set(PerPageHeader, strconv.Itoa(p.perPage))
 
 	last := p.last
 
+	if OmitPaginationWhenSinglePage && last == 1 {
+		w.Header().Set(TotalItemsHeader, strconv.Itoa(p.total))
+		w.Header().Set(TotalPagesHeader, strconv.Itoa(last))
+		return
+	}
+
 	if p.page != last {
 		w.Header().Set(NextPageHeader, strconv.Itoa(p.Next()))
 		w.Header().Add(LinkHeader, fmt.Sprintf(Linkf, p.NextURL(), "next"))
@@ -286,11 +379,70 @@ type simpleBody struct {
 	Next    string      `json:"next,omitempty" xml:"next,omitempty"`
 	Prev    string      `json:"prev,omitempty" xml:"prev,omitempty"`
 	Last    string      `json:"last,omitempty" xml:"last,omitempty"`
-	Items   interface{} `json:"items" xml:"items"`
+	Items   interface{} `json:"-" xml:"-"`
+}
+
+// MarshalJSON encodes body with payload under PaginationItemsKey using
+// JSONEncoder.
+func (b simpleBody) MarshalJSON() ([]byte, error) {
+	type body simpleBody
+	meta, err := marshalJSON(body(b))
+	if err != nil {
+		return nil, err
+	}
+	key, err := marshalJSON(PaginationItemsKey)
+	if err != nil {
+		return nil, err
+	}
+	items, err := marshalJSON(b.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(meta)+len(key)+len(items)+2))
+	buf.Write(meta[:len(meta)-1])
+	buf.WriteByte(',')
+	buf.Write(key)
+	buf.WriteByte(':')
+	buf.Write(items)
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalXML encodes body with payload under PaginationItemsKey.
+func (b simpleBody) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	elements := []struct {
+		name      string
+		value     interface{}
+		omitempty bool
+	}{
+		{"page", b.Page, false},
+		{"per_page", b.PerPage, false},
+		{"total", b.Total, false},
+		{"next", b.Next, true},
+		{"prev", b.Prev, true},
+		{"last", b.Last, true},
+		{PaginationItemsKey, b.Items, false},
+	}
+	for _, element := range elements {
+		if element.omitempty && element.value == "" {
+			continue
+		}
+		if err := e.EncodeElement(element.value, xml.StartElement{Name: xml.Name{Local: element.name}}); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
 }
 
 // DefaultPaginationBody returns custom pagination body.
 func DefaultPaginationBody(p Pagination, v interface{}) interface{} {
+	p = p.resolved()
 	return simpleBody{
 		Page:    p.page,
 		PerPage: p.perPage,
@@ -77,6 +77,60 @@ func TestNewPagination(t *testing.T) {
 	utest.Equals(t, 100, got.Total())
 }
 
+func TestNewPaginationLazy(t *testing.T) {
+	calls := 0
+	count := func() int {
+		calls++
+		return 100
+	}
+
+	got := render.NewPaginationLazy(defaultURL(2, 20), count)
+
+	utest.Equals(t, 2, got.Page())
+	utest.Equals(t, 20, got.PerPage())
+	utest.Equals(t, 3, got.Next())
+	utest.Equals(t, "http://localhost/users?page=3&per_page=20", got.NextURL())
+	utest.Equals(t, 0, calls)
+
+	utest.Equals(t, 5, got.Last())
+	utest.Equals(t, 100, got.Total())
+	utest.Equals(t, "http://localhost/users?page=5&per_page=20", got.LastURL())
+	utest.Equals(t, 1, calls)
+}
+
+func TestNewPaginationLazy_Render(t *testing.T) {
+	tests := []struct {
+		name  string
+		page  int
+		items int
+		calls int
+		total string
+	}{
+		{name: "last page", page: 3, items: 5, calls: 0, total: "45"},
+		{name: "empty first page", page: 1, items: 0, calls: 0, total: "0"},
+		{name: "full page", page: 2, items: 20, calls: 1, total: "100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			count := func() int {
+				calls++
+				return 100
+			}
+			r := request(tt.page, 20)
+			r.Header = http.Header{render.AcceptHeader: []string{render.ApplicationJSON}}
+			w := httptest.NewRecorder()
+
+			p := render.NewPaginationLazy(r.URL, count)
+			p.Render(w, r, make([]int, tt.items))
+
+			utest.Equals(t, http.StatusOK, w.Code)
+			utest.Equals(t, tt.calls, calls)
+			utest.Equals(t, tt.total, w.Header().Get(render.TotalItemsHeader))
+		})
+	}
+}
+
 func TestPagination_URL(t *testing.T) {
 	uri := defaultURL(1, 20)
 
@@ -209,6 +263,25 @@ func TestDefaultPaginationHeader(t *testing.T) {
 	render.PaginationHeader = refHeaderFunc
 }
 
+func TestOmitPaginationWhenSinglePage(t *testing.T) {
+	render.OmitPaginationWhenSinglePage = true
+	defer func() {
+		render.OmitPaginationWhenSinglePage = false
+	}()
+
+	w := httptest.NewRecorder()
+	r := request(1, 20)
+
+	render.PaginationFromRequest(r, 3).Render(w, r, []string{"Enver", "Joe", "Dave"})
+
+	utest.Equals(t, http.StatusOK, w.Code)
+	utest.Equals(t, "3", w.Header().Get(render.TotalItemsHeader))
+	utest.Equals(t, "1", w.Header().Get(render.TotalPagesHeader))
+	utest.Equals(t, "", w.Header().Get(render.NextPageHeader))
+	utest.Equals(t, "", w.Header().Get(render.PrevPageHeader))
+	utest.Equals(t, []string(nil), w.Header().Values(render.LinkHeader))
+}
+
 func TestDefaultPaginationBody(t *testing.T) {
 	type user struct {
 		Name string `json:"name"`
@@ -262,3 +335,98 @@ func TestDefaultPaginationBody(t *testing.T) {
 	render.PaginationInHeader = refPaginationInHeader
 	render.PaginationBody = refBodyFunc
 }
+
+func TestPaginationInHeaderAndBody(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+	w := httptest.NewRecorder()
+	r := request(1, 20)
+	v := []user{
+		{
+			Name: "Enver",
+		},
+	}
+
+	refPaginationInHeaderAndBody := render.PaginationInHeaderAndBody
+	render.PaginationInHeaderAndBody = true
+
+	pagination := render.PaginationFromRequest(r, 100)
+	pagination.Render(w, r, v)
+
+	utest.Equals(t, "1", w.Header().Get(render.PageHeader))
+	utest.Equals(t, "20", w.Header().Get(render.PerPageHeader))
+
+	body := struct {
+		Page    int    `json:"page"`
+		PerPage int    `json:"per_page"`
+		Total   int    `json:"total"`
+		Items   []user `json:"items"`
+	}{}
+	err := json.Unmarshal(w.Body.Bytes(), &body)
+	utest.OK(t, err)
+
+	utest.Equals(t, 1, body.Page)
+	utest.Equals(t, 20, body.PerPage)
+	utest.Equals(t, 100, body.Total)
+	utest.Equals(t, v, body.Items)
+
+	render.PaginationInHeaderAndBody = refPaginationInHeaderAndBody
+}
+
+func TestPaginationItemsKey(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+	w := httptest.NewRecorder()
+	r := request(1, 20)
+	v := []user{
+		{
+			Name: "Enver",
+		},
+	}
+
+	refPaginationInHeader := render.PaginationInHeader
+	render.PaginationInHeader = false
+	refPaginationItemsKey := render.PaginationItemsKey
+	render.PaginationItemsKey = "data"
+
+	pagination := render.PaginationFromRequest(r, 100)
+	pagination.Render(w, r, v)
+
+	body := map[string]json.RawMessage{}
+	err := json.Unmarshal(w.Body.Bytes(), &body)
+	utest.OK(t, err)
+
+	_, ok := body["items"]
+	utest.Assert(t, !ok, "unexpected items key in body %s", w.Body.String())
+	utest.Equals(t, `[{"name":"Enver"}]`, string(body["data"]))
+
+	render.PaginationInHeader = refPaginationInHeader
+	render.PaginationItemsKey = refPaginationItemsKey
+}
+
+func TestPaginationBody_JSONEncoder(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := request(1, 20)
+
+	refPaginationInHeader := render.PaginationInHeader
+	render.PaginationInHeader = false
+	refJSONEncoder := render.JSONEncoder
+	render.JSONEncoder = func(w io.Writer) render.Encoder {
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return enc
+	}
+
+	pagination := render.PaginationFromRequest(r, 100)
+	pagination.Render(w, r, []string{"<b>x</b>"})
+
+	utest.Equals(t, `{"page":1,"per_page":20,"total":100,`+
+		`"next":"http://localhost/users?page=2&per_page=20",`+
+		`"last":"http://localhost/users?page=5&per_page=20",`+
+		`"items":["<b>x</b>"]}`+"\n", w.Body.String())
+
+	render.PaginationInHeader = refPaginationInHeader
+	render.JSONEncoder = refJSONEncoder
+}
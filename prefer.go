@@ -0,0 +1,79 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Header names used for client preferences (RFC 7240)
+const (
+	PreferHeader            = "Prefer"
+	PreferenceAppliedHeader = "Preference-Applied"
+)
+
+// Values of return preference
+const (
+	PreferReturnMinimal        = "minimal"
+	PreferReturnRepresentation = "representation"
+)
+
+// PreferReturn returns value of return preference from request Prefer
+// header, for example "minimal" for `Prefer: return=minimal`. Empty string is
+// returned when preference is not set.
+func PreferReturn(r *http.Request) string {
+	for _, header := range r.Header.Values(PreferHeader) {
+		for _, preference := range strings.Split(header, ",") {
+			kv := strings.SplitN(strings.Split(preference, ";")[0], "=", 2)
+			if len(kv) == 2 && strings.EqualFold(strings.TrimSpace(kv[0]), "return") {
+				return strings.ToLower(strings.Trim(strings.TrimSpace(kv[1]), `"`))
+			}
+		}
+	}
+	return ""
+}
+
+// applyPreferReturn handles return preference for 200 OK, 201 Created and
+// 202 Accepted responses of POST, PUT and PATCH requests. Other statuses, like
+// 207 Multi-Status, carry information in the body and are left untouched.
+// For return=minimal 204 No Content is written and true is returned.
+func applyPreferReturn(w http.ResponseWriter, r *http.Request, params []interface{}) bool {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return false
+	}
+	switch paramsStatus(params) {
+	case 0, http.StatusOK, http.StatusCreated, http.StatusAccepted:
+	default:
+		return false
+	}
+
+	switch preference := PreferReturn(r); preference {
+	case PreferReturnMinimal:
+		w.Header().Set(PreferenceAppliedHeader, "return="+preference)
+		NoContent(w)
+		return true
+	case PreferReturnRepresentation:
+		w.Header().Set(PreferenceAppliedHeader, "return="+preference)
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
+)
+
+func TestPreferReturn(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/users", nil)
+	r.Header.Set(render.PreferHeader, `respond-async, return=minimal; foo="bar"`)
+
+	utest.Equals(t, render.PreferReturnMinimal, render.PreferReturn(r))
+	utest.Equals(t, "", render.PreferReturn(httptest.NewRequest(http.MethodGet, "/", nil)))
+}
+
+func TestDefaultResponder_PreferReturn(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefer  string
+		status  int
+		applied string
+		body    string
+	}{
+		{
+			name:    "return=minimal",
+			prefer:  "return=minimal",
+			status:  http.StatusNoContent,
+			applied: "return=minimal",
+			body:    "",
+		},
+		{
+			name:    "return=representation",
+			prefer:  "return=representation",
+			status:  http.StatusCreated,
+			applied: "return=representation",
+			body:    `{"name":"Enver"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/users", nil)
+			r.Header.Set(render.AcceptHeader, render.ApplicationJSON)
+			r.Header.Set(render.PreferHeader, tt.prefer)
+			w := httptest.NewRecorder()
+
+			render.Render(w, r, map[string]string{"name": "Enver"}, http.StatusCreated)
+
+			utest.Equals(t, tt.status, w.Code)
+			utest.Equals(t, tt.applied, w.Header().Get(render.PreferenceAppliedHeader))
+			utest.Equals(t, tt.body, w.Body.String())
+		})
+	}
+}
+
+func TestError_PreferReturnMinimal(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/users", nil)
+	r.Header.Set(render.AcceptHeader, render.ApplicationJSON)
+	r.Header.Set(render.PreferHeader, "return=minimal")
+	w := httptest.NewRecorder()
+
+	render.Error(w, r, errors.New("bad input"), http.StatusBadRequest)
+
+	utest.Equals(t, http.StatusBadRequest, w.Code)
+	utest.Equals(t, "", w.Header().Get(render.PreferenceAppliedHeader))
+}
+
+func TestDefaultResponder_PreferReturnSafeMethod(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	r.Header.Set(render.AcceptHeader, render.ApplicationJSON)
+	r.Header.Set(render.PreferHeader, "return=minimal")
+	w := httptest.NewRecorder()
+
+	render.Render(w, r, map[string]string{"name": "Enver"})
+
+	utest.Equals(t, http.StatusOK, w.Code)
+	utest.Equals(t, "", w.Header().Get(render.PreferenceAppliedHeader))
+	utest.Equals(t, `{"name":"Enver"}`+"\n", w.Body.String())
+}
+
+func TestDefaultResponder_PreferReturnMultiStatus(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPatch, "/users", nil)
+	r.Header.Set(render.AcceptHeader, render.ApplicationJSON)
+	r.Header.Set(render.PreferHeader, "return=minimal")
+	w := httptest.NewRecorder()
+
+	ms := render.MultiStatus{}
+	ms.Add("/users/2", http.StatusNotFound, render.DefaultErrorRespond(r, render.ErrNotFound))
+	ms.Render(w, r)
+
+	utest.Equals(t, http.StatusMultiStatus, w.Code)
+	utest.Equals(t, "", w.Header().Get(render.PreferenceAppliedHeader))
+	utest.Equals(t, `{"responses":[{"href":"/users/2","status":404,"body":{"message":"not found"}}]}`+"\n", w.Body.String())
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"net/http"
+)
+
+// MIME types for RFC 7807 problem details
+const (
+	ApplicationProblemJSON = "application/problem+json"
+	ApplicationProblemXML  = "application/problem+xml"
+)
+
+// StatusTitle contains custom problem details titles per status code, for
+// statuses not in the map http.StatusText is used.
+var StatusTitle = map[int]string{}
+
+// statusTitle returns problem details title for status.
+func statusTitle(status int) string {
+	if title, ok := StatusTitle[status]; ok {
+		return title
+	}
+	return http.StatusText(status)
+}
+
+// ProblemDetail represents RFC 7807 problem details object.
+type ProblemDetail struct {
+	XMLName  xml.Name `json:"-" xml:"urn:ietf:rfc:7807 problem"`
+	Type     string   `json:"type,omitempty" xml:"type,omitempty"`
+	Title    string   `json:"title,omitempty" xml:"title,omitempty"`
+	Status   int      `json:"status,omitempty" xml:"status,omitempty"`
+	Detail   string   `json:"detail,omitempty" xml:"detail,omitempty"`
+	Instance string   `json:"instance,omitempty" xml:"instance,omitempty"`
+}
+
+// NewProblemDetail returns problem details object for error and status. Type
+// is documentation URL of the error when its code is registered in ErrorCodes
+// and ErrorDocsBaseURL is set, otherwise "about:blank".
+func NewProblemDetail(r *http.Request, err error, status int) ProblemDetail {
+	typ := errorDocsURL(errorCode(err))
+	if typ == "" {
+		typ = "about:blank"
+	}
+	return ProblemDetail{
+		Type:   typ,
+		Title:  statusTitle(status),
+		Status: status,
+		Detail: err.Error(),
+	}
+}
+
+// Problem renders RFC 7807 problem details response. Content type is
+// application/problem+xml when client accepts XML, otherwise
+// application/problem+json. Status code is resolved and OnError is called the
+// same way as in Error.
+func Problem(w http.ResponseWriter, r *http.Request, err error, params ...interface{}) {
+	status, err := errorStatus(err)
+	if s := paramsStatus(params); s != 0 {
+		status = s
+	}
+	OnError(r, err, ClassifyStatus(status))
+	problem := NewProblemDetail(r, err, status)
+
+	buf := &bytes.Buffer{}
+	contentType := ApplicationProblemJSON
+	if GetAcceptedContentType(r) == ContentTypeXML {
+		contentType = ApplicationProblemXML
+		buf.WriteString(xml.Header)
+		err = XMLEncoder(buf).Encode(problem)
+	} else {
+		err = JSONEncoder(buf).Encode(problem)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	Blob(w, buf.Bytes(), append(params, status, ContentTypeHeader, contentType)...)
+}
+
+// errorStatus returns status code for error and unwrapped error value.
+func errorStatus(err error) (int, error) {
+	status := http.StatusInternalServerError
+	// find in map of default errors and return status
+	for key, value := range ErrorMap {
+		if errors.Is(err, key) {
+			status = value
+		}
+	}
+	// http error checking
+	httpError := &HTTPError{}
+	if errors.As(err, &httpError) {
+		status = httpError.Status
+		err = httpError.Err
+	}
+	return status, err
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
+)
+
+func TestProblem(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{
+			name:        "problem json",
+			accept:      render.ApplicationJSON,
+			contentType: render.ApplicationProblemJSON,
+			body:        `{"type":"about:blank","title":"Not Found","status":404,"detail":"not found"}` + "\n",
+		},
+		{
+			name:        "problem xml",
+			accept:      render.ApplicationXML,
+			contentType: render.ApplicationProblemXML,
+			body: xml.Header + `<problem xmlns="urn:ietf:rfc:7807">` +
+				`<type>about:blank</type><title>Not Found</title><status>404</status><detail>not found</detail>` +
+				`</problem>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &http.Request{
+				URL: &url.URL{},
+				Header: http.Header{
+					render.AcceptHeader: []string{tt.accept},
+				},
+			}
+			w := httptest.NewRecorder()
+
+			render.Problem(w, r, render.ErrNotFound)
+
+			utest.Equals(t, http.StatusNotFound, w.Code)
+			utest.Equals(t, tt.contentType, w.Header().Get(render.ContentTypeHeader))
+			utest.Equals(t, tt.body, w.Body.String())
+		})
+	}
+}
+
+func TestProblem_StatusTitle(t *testing.T) {
+	refStatusTitle := render.StatusTitle
+	render.StatusTitle = map[int]string{
+		http.StatusNotFound: "Nije pronađeno",
+	}
+
+	r := &http.Request{
+		URL: &url.URL{},
+		Header: http.Header{
+			render.AcceptHeader: []string{render.ApplicationJSON},
+		},
+	}
+	w := httptest.NewRecorder()
+
+	render.Problem(w, r, render.ErrNotFound)
+
+	utest.Equals(t, `{"type":"about:blank","title":"Nije pronađeno","status":404,"detail":"not found"}`+"\n", w.Body.String())
+
+	render.StatusTitle = refStatusTitle
+}
+
+func TestProblem_OnError(t *testing.T) {
+	var (
+		onErr error
+		class render.ErrorClass
+	)
+	refOnError := render.OnError
+	render.OnError = func(r *http.Request, err error, c render.ErrorClass) {
+		onErr = err
+		class = c
+	}
+	defer func() {
+		render.OnError = refOnError
+	}()
+
+	r := &http.Request{
+		URL: &url.URL{},
+		Header: http.Header{
+			render.AcceptHeader: []string{render.ApplicationJSON},
+		},
+	}
+
+	render.Problem(httptest.NewRecorder(), r, render.ErrNotFound)
+	utest.Equals(t, render.ErrNotFound, onErr)
+	utest.Equals(t, render.ClientError, class)
+
+	render.Problem(httptest.NewRecorder(), r, render.ErrNotFound, http.StatusInternalServerError)
+	utest.Equals(t, render.ServerError, class)
+}
@@ -25,16 +25,24 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // Header names used in request/response
 const (
-	ContentTypeHeader = "Content-Type"
-	AcceptHeader      = "Accept"
+	ContentTypeHeader  = "Content-Type"
+	AcceptHeader       = "Accept"
+	LocationHeader     = "Location"
+	RangeHeader        = "Range"
+	ContentRangeHeader = "Content-Range"
 )
 
 // Respond is a package-level variable set to our default Responder. We do this
@@ -43,14 +51,80 @@ const (
 // itself. Effectively, allowing you to easily add your own logic to the package
 // defaults. For example, maybe you want to test if v is an error and respond
 // differently, or log something before you respond.
-var Respond = DefaultResponder
+var Respond func(w http.ResponseWriter, r *http.Request, v interface{}, params ...interface{})
+
+//nolint:gochecknoinits
+func init() {
+	// assigned in init to break initialization cycle, Error used by
+	// renderers refers to Respond
+	Respond = DefaultResponder
+}
 
+// MaxResponseBytes limits size of buffered responses, zero means no limit.
+// Responses exceeding the limit are replaced with ErrResponseTooLarge error.
+var MaxResponseBytes = 0
+
+// DefaultStatusByMethod contains status codes used by DefaultResponder when
+// status is not provided in params, for example:
+//
+//	render.DefaultStatusByMethod = map[string]int{
+//		http.MethodPost:   http.StatusCreated,
+//		http.MethodDelete: http.StatusNoContent,
+//	}
+var DefaultStatusByMethod = map[string]int{}
+
+// UnwrapSingle renders slice with exactly one element as the element itself
+// instead of an array, for clients expecting a single object.
+var UnwrapSingle = false
+
+// CollectionEnvelope is a package-level variable set to function wrapping
+// collection responses (slices, arrays and channels), other values are
+// rendered as is. Nothing is wrapped when it is nil, for example:
+//
+//	render.CollectionEnvelope = func(r *http.Request, v interface{}) interface{} {
+//		return map[string]interface{}{"data": v}
+//	}
+var CollectionEnvelope func(r *http.Request, v interface{}) interface{}
+
+// AfterRender is a package-level variable set to function called after
+// DefaultResponder has written the response, with final status code and
+// number of body bytes written. Status is zero when nothing was written, for
+// example when request was canceled. It can be used for metrics or tracing,
+// nothing is called when it is nil.
+var AfterRender func(r *http.Request, status, bytes int)
+
+var (
+	// StreamCloseEvent is name of the event sent by Stream when channel is
+	// closed.
+	StreamCloseEvent = "EOF"
+	// StreamCloseData is data of the event sent by Stream when channel is
+	// closed, no data line is sent when empty.
+	StreamCloseData = ""
+)
+
+// formats maps `format` query param values to Accept header values.
 var formats = map[string][]string{
 	"txt":    {TextPlain},
 	"json":   {ApplicationJSON},
 	"xml":    {ApplicationXML},
 	"html":   {TextHTML},
 	"stream": {TextEventStream},
+	"csv":    {TextCSV},
+	"toml":   {ApplicationTOML},
+}
+
+// RegisterFormat adds `format` query param value name which sets Accept
+// header to contentType, for example:
+//
+//	render.RegisterFormat("text", render.TextPlain)
+//
+// RegisterFormat only adds an alias, contentType must be one DefaultResponder
+// renders, like JSON, XML, plain text or CSV. Other content types are handled
+// as unknown Accept header, DefaultContentType is rendered or 406 Not
+// Acceptable under NegotiationStrict. Existing format with the same name is replaced. RegisterFormat is not safe
+// for concurrent use and should be called on init.
+func RegisterFormat(name, contentType string) {
+	formats[name] = []string{contentType}
 }
 
 // Encoder provide method for encoding reader data
@@ -72,6 +146,45 @@ func DefaultJSONEncoder(w io.Writer) Encoder {
 	return enc
 }
 
+// IndentJSONEncoder creates JSONEncoder encoder with indented output. Output
+// of encoders without SetIndent method is indented after encoding.
+func IndentJSONEncoder(w io.Writer) Encoder {
+	enc := JSONEncoder(w)
+	if indenter, ok := enc.(interface{ SetIndent(prefix, indent string) }); ok {
+		indenter.SetIndent("", "  ")
+		return enc
+	}
+	return indentEncoder{w: w}
+}
+
+// indentEncoder indents output of JSONEncoder.
+type indentEncoder struct {
+	w io.Writer
+}
+
+// Encode encodes v with JSONEncoder and writes indented output.
+func (e indentEncoder) Encode(v interface{}) error {
+	buf := &bytes.Buffer{}
+	if err := JSONEncoder(buf).Encode(v); err != nil {
+		return err
+	}
+	out := &bytes.Buffer{}
+	if err := json.Indent(out, buf.Bytes(), "", "  "); err != nil {
+		return err
+	}
+	_, err := e.w.Write(out.Bytes())
+	return err
+}
+
+// marshalJSON returns v encoded with JSONEncoder, without trailing newline.
+func marshalJSON(v interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := JSONEncoder(buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
 // DefaultXMLEncoder creates default XML encoder
 func DefaultXMLEncoder(w io.Writer) Encoder {
 	return xml.NewEncoder(w)
@@ -80,25 +193,82 @@ func DefaultXMLEncoder(w io.Writer) Encoder {
 // DefaultResponder handles streaming JSON and XML responses, automatically setting the
 // Content-Type based on request headers or query param `format`. Default content type is JSON.
 func DefaultResponder(w http.ResponseWriter, r *http.Request, v interface{}, params ...interface{}) {
-	format, ok := formats[r.URL.Query().Get("format")]
-	if ok {
-		r.Header.Set(AcceptHeader, strings.Join(format, ","))
+	rw, wrapped := wrapWriter(w, r)
+	if wrapped && AfterRender != nil {
+		defer func() {
+			AfterRender(r, rw.Status(), rw.bytes)
+		}()
+	}
+	w = rw
+
+	if name := r.URL.Query().Get("format"); name != "" {
+		format, ok := formats[name]
+		switch {
+		case ok:
+			r.Header.Set(AcceptHeader, strings.Join(format, ","))
+		case Negotiation == NegotiationStrict:
+			notAcceptable(w)
+			return
+		}
 	}
 
-	if reflect.TypeOf(v).Kind() == reflect.Chan {
+	if applyPreferReturn(w, r, params) {
+		return
+	}
+
+	status := paramsStatus(params)
+	if status == 0 {
+		if status = DefaultStatusByMethod[r.Method]; status != 0 {
+			params = append(params, status)
+		}
+	}
+	if status == http.StatusNoContent {
+		NoContent(w)
+		return
+	}
+
+	if v != nil && reflect.TypeOf(v).Kind() == reflect.Chan {
 		v = channelIntoSlice(w, r, v)
 	}
 
+	if UnwrapSingle {
+		v = unwrapSingle(v)
+	}
+
+	if CollectionEnvelope != nil && isCollection(v) {
+		v = CollectionEnvelope(r, v)
+	}
+
+	contentType := Negotiator(r, availableContentTypes)
+	if contentType == ContentTypeUnknown {
+		if Negotiation == NegotiationStrict {
+			notAcceptable(w)
+			return
+		}
+		contentType = DefaultContentType
+	}
+
 	// Format response based on request Accept header.
-	switch GetAcceptedContentType(r) {
-	case ContentTypePlainText, ContentTypeUnknown:
+	switch contentType {
+	case ContentTypePlainText:
 		PlainText(w, v, params...)
 	case ContentTypeJSON:
+		if wantsPretty(r) {
+			params = append(params, IndentJSONEncoder)
+		}
+		if acceptsJSONLD(r) {
+			JSONLD(w, r, v, params...)
+			break
+		}
 		JSON(w, v, params...)
 	case ContentTypeXML:
 		XML(w, v, params...)
 	case ContentTypeEventStream:
 		Stream(w, r, v)
+	case ContentTypeCSV:
+		CSV(w, v, params...)
+	case ContentTypeTOML:
+		TOML(w, v, params...)
 	case ContentTypeForm:
 		// TBD
 		fallthrough
@@ -110,6 +280,35 @@ func DefaultResponder(w http.ResponseWriter, r *http.Request, v interface{}, par
 	}
 }
 
+// unwrapSingle returns the only element of slice or array v, other values are
+// returned unchanged.
+func unwrapSingle(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Len() == 1 && rv.Type().Elem().Kind() != reflect.Uint8 {
+			return rv.Index(0).Interface()
+		}
+	}
+	return v
+}
+
+// isCollection reports whether v is slice or array, byte slices are not
+// considered collections.
+func isCollection(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return rv.Type().Elem().Kind() != reflect.Uint8
+	}
+	return false
+}
+
+// notAcceptable responds with 406 Not Acceptable status.
+func notAcceptable(w http.ResponseWriter) {
+	http.Error(w, ErrNotAcceptable.Error(), http.StatusNotAcceptable)
+}
+
 // Bind decodes a request body and executes the Binder method of the
 // payload structure.
 func Bind(r *http.Request, v interface{}) error {
@@ -121,6 +320,12 @@ func Render(w http.ResponseWriter, r *http.Request, v interface{}, params ...int
 	Respond(w, r, v, params...)
 }
 
+// RenderStatus renders payload with explicit status code and respond to the
+// client request. Status takes precedence over any int value in params.
+func RenderStatus(w http.ResponseWriter, r *http.Request, status int, v interface{}, params ...interface{}) {
+	Respond(w, r, v, append([]interface{}{status}, params...)...)
+}
+
 // Blob writes raw bytes to the response, the default Content-Type as
 // application/octet-stream, params is optional which can be int or string type.
 // Int will provide status code and string is for header pair values
@@ -140,10 +345,13 @@ func Render(w http.ResponseWriter, r *http.Request, v interface{}, params ...int
 //		 "Content-Type": []string{"application/json"},
 //	}, http.StatusOK)
 //
-// the order of the parameters does not matter.
+// the order of the parameters does not matter. When Compression is enabled
+// body is gzip compressed based on request Accept-Encoding header. Written
+// body is mirrored to writers passed with WithTee.
 func Blob(w http.ResponseWriter, v []byte, params ...interface{}) {
 	w.Header().Set(ContentTypeHeader, "application/octet-stream")
 	status, key, value := 0, "", ""
+	var tees []io.Writer
 	for _, param := range params {
 		if rv := reflect.ValueOf(param); rv.Kind() == reflect.Ptr {
 			param = rv.Elem().Interface()
@@ -170,6 +378,8 @@ func Blob(w http.ResponseWriter, v []byte, params ...interface{}) {
 			for key, values := range arg {
 				w.Header().Set(key, strings.Join(values, ","))
 			}
+		case Tee:
+			tees = append(tees, arg.w)
 		}
 	}
 
@@ -177,17 +387,85 @@ func Blob(w http.ResponseWriter, v []byte, params ...interface{}) {
 		status = http.StatusOK
 	}
 
+	body, ok := compressBody(w, v)
+	if !ok {
+		notAcceptable(w)
+		return
+	}
+
 	w.WriteHeader(status)
-	w.Write(v) //nolint:errcheck
+	w.Write(body) //nolint:errcheck
+	if len(tees) > 0 {
+		// tees get response body before compression
+		io.MultiWriter(tees...).Write(v) //nolint:errcheck
+	}
+}
+
+// Tee is Blob param which mirrors response body to additional writer.
+type Tee struct {
+	w io.Writer
+}
+
+// WithTee returns Blob param which writes copy of response body to w, for
+// example to keep audit log of responses. Copy is not compressed, even when
+// response is:
+//
+//	render.Render(w, r, v, render.WithTee(auditLog))
+func WithTee(w io.Writer) Tee {
+	return Tee{w: w}
+}
+
+// paramsStatus returns first non zero status code from params.
+func paramsStatus(params []interface{}) int {
+	for _, param := range params {
+		if rv := reflect.ValueOf(param); rv.Kind() == reflect.Ptr {
+			param = rv.Elem().Interface()
+		}
+		if status, ok := param.(int); ok && status != 0 {
+			return status
+		}
+	}
+	return 0
+}
+
+// paramsEncoder returns first encoder function from params or def.
+func paramsEncoder(params []interface{}, def func(w io.Writer) Encoder) func(w io.Writer) Encoder {
+	for _, param := range params {
+		if enc, ok := param.(func(w io.Writer) Encoder); ok {
+			return enc
+		}
+	}
+	return def
+}
+
+// wantsPretty reports whether client requested indented output with `pretty`
+// query param or `pretty` parameter of accepted JSON media type, for example
+// `Accept: application/json; pretty=true`.
+func wantsPretty(r *http.Request) bool {
+	if values, ok := r.URL.Query()["pretty"]; ok {
+		if values[0] == "" {
+			return true
+		}
+		pretty, err := strconv.ParseBool(values[0])
+		return err == nil && pretty
+	}
+	if mr, ok := acceptedJSON(r); ok {
+		pretty, err := strconv.ParseBool(mr.params["pretty"])
+		return err == nil && pretty
+	}
+	return false
 }
 
 // PlainText writes a string to the response, setting the Content-Type as
-// text/plain.
+// text/plain. Pass request in params to transcode response to charset
+// negotiated with Accept-Charset header, see CharsetTranscoding.
 func PlainText(w http.ResponseWriter, v interface{}, params ...interface{}) {
 	templateFactory(w, newTemplateWrapper("text"), v, "text/plain; charset=utf-8", params...)
 }
 
 // HTML writes a string to the response, setting the Content-Type as text/html.
+// Pass request in params to transcode response to charset negotiated with
+// Accept-Charset header, see CharsetTranscoding.
 func HTML(w http.ResponseWriter, v interface{}, params ...interface{}) {
 	templateFactory(w, newTemplateWrapper("html"), v, "text/html; charset=utf-8", params...)
 }
@@ -195,18 +473,46 @@ func HTML(w http.ResponseWriter, v interface{}, params ...interface{}) {
 // JSON marshals 'v' to JSON, automatically escaping HTML and setting the
 // Content-Type as application/json.
 func JSON(w http.ResponseWriter, v interface{}, params ...interface{}) {
-	buf := &bytes.Buffer{}
-	if err := JSONEncoder(buf).Encode(v); err != nil {
+	if requestCanceled(w) {
+		return
+	}
+	b, err := encodeJSON(v, params)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	Blob(w, buf.Bytes(), append(params, ContentTypeHeader, ApplicationJSONExt)...)
+	if requestCanceled(w) || exceedsMaxResponse(w, b) {
+		return
+	}
+	Blob(w, b, append(params, ContentTypeHeader, ApplicationJSONExt)...)
+}
+
+// encodeJSON encodes v using encoder from params or JSONEncoder.
+func encodeJSON(v interface{}, params []interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := paramsEncoder(params, JSONEncoder)(buf).Encode(v); err != nil {
+		return nil, err
+	}
+	b := buf.Bytes()
+	if StripNulls {
+		var err error
+		if b, err = stripNulls(b); err != nil {
+			return nil, err
+		}
+	}
+	if DeprecationWarnings {
+		return appendWarnings(b, v)
+	}
+	return b, nil
 }
 
 // XML marshals 'v' to JSON, setting the Content-Type as application/xml. It
 // will automatically prepend a generic XML header (see encoding/xml.Header) if
 // one is not found in the first 100 bytes of 'v'.
 func XML(w http.ResponseWriter, v interface{}, params ...interface{}) {
+	if requestCanceled(w) {
+		return
+	}
 	buf := &bytes.Buffer{}
 	if err := XMLEncoder(buf).Encode(v); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -221,23 +527,44 @@ func XML(w http.ResponseWriter, v interface{}, params ...interface{}) {
 	}
 	if !bytes.Contains(b[:findHeaderUntil], []byte("<?xml")) {
 		// No header found. Print it out first.
-		w.Write([]byte(xml.Header)) //nolint:errcheck
+		b = append([]byte(xml.Header), b...)
+	}
+	if requestCanceled(w) || exceedsMaxResponse(w, b) {
+		return
 	}
 
 	Blob(w, b, append(params, ContentTypeHeader, "application/xml; charset=utf-8")...)
 }
 
-// File sends a response with the content of the file.
+// File sends a response with the content of the file. When Compression is
+// enabled text-like files, by extension, are gzip compressed for clients
+// accepting gzip content coding.
 func File(w http.ResponseWriter, r *http.Request, fullPath string) {
-	w.Header().Set("Content-Disposition", "attachment; filename="+strconv.Quote(fullPath))
+	w.Header().Set("Content-Disposition", contentDisposition("attachment", fullPath))
 	w.Header().Set(ContentTypeHeader, "application/octet-stream")
-	http.ServeFile(w, r, fullPath)
+
+	if !Compression || !compressibleType(mime.TypeByExtension(filepath.Ext(fullPath))) {
+		http.ServeFile(w, r, fullPath)
+		return
+	}
+	w.Header().Add(VaryHeader, AcceptEncodingHeader)
+	if gzipQ, _ := encodingQuality(r); gzipQ <= 0 {
+		http.ServeFile(w, r, fullPath)
+		return
+	}
+
+	// byte ranges of compressed content can't be served
+	r = r.Clone(r.Context())
+	r.Header.Del(RangeHeader)
+	gw := &gzipResponseWriter{ResponseWriter: w}
+	defer gw.Close() //nolint:errcheck
+	http.ServeFile(gw, r, fullPath)
 }
 
 // Attachment sends a response as attachment, prompting client to save the
 // file.
 func Attachment(w http.ResponseWriter, r *http.Request, fullPath string) {
-	w.Header().Set("Content-Disposition", "attachment")
+	w.Header().Set("Content-Disposition", contentDisposition("attachment", fullPath))
 	w.Header().Set(ContentTypeHeader, "application/octet-stream")
 	http.ServeFile(w, r, fullPath)
 }
@@ -249,12 +576,67 @@ func Inline(w http.ResponseWriter, r *http.Request, fullPath string) {
 	http.ServeFile(w, r, fullPath)
 }
 
+// contentDisposition returns Content-Disposition header value with base name
+// of fullPath as ASCII filename and RFC 5987 encoded filename* parameter.
+func contentDisposition(disposition, fullPath string) string {
+	name := filepath.Base(fullPath)
+	return fmt.Sprintf("%s; filename=%s; filename*=UTF-8''%s",
+		disposition, strconv.Quote(asciiFilename(name)), encodeRFC5987(name))
+}
+
+// asciiFilename returns ASCII only version of name, diacritics are removed and
+// other non ASCII or control characters are replaced with underscore.
+func asciiFilename(name string) string {
+	var buf strings.Builder
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r > unicode.MaxASCII, unicode.IsControl(r), r == '"', r == '\\':
+			buf.WriteRune('_')
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+// encodeRFC5987 percent encodes s, leaving only attr-char characters as is.
+func encodeRFC5987(s string) string {
+	const hex = "0123456789ABCDEF"
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			strings.IndexByte("!#$&+-.^_`|~", c) >= 0:
+			buf.WriteByte(c)
+		default:
+			buf.WriteByte('%')
+			buf.WriteByte(hex[c>>4])
+			buf.WriteByte(hex[c&0x0f])
+		}
+	}
+	return buf.String()
+}
+
 // NoContent returns a HTTP 204 "No Content" response.
 func NoContent(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// Stream sends a streaming response with status code and content type.
+// CreatedEmpty returns a 201 Created response with Location header set to
+// location and without body.
+func CreatedEmpty(w http.ResponseWriter, location string) {
+	w.Header().Set(LocationHeader, location)
+	w.Header().Del(ContentTypeHeader)
+	w.Header().Del(ContentEncodingHeader)
+	w.Header().Set("Content-Length", "0")
+	w.WriteHeader(http.StatusCreated)
+}
+
+// Stream sends a streaming response with status code and content type. When
+// channel is closed StreamCloseEvent is sent and response is flushed.
 func Stream(w http.ResponseWriter, r *http.Request, v interface{}) {
 	if reflect.TypeOf(v).Kind() != reflect.Chan {
 		panic(fmt.Sprintf("render: event stream expects a channel, not %v", reflect.TypeOf(v).Kind()))
@@ -283,7 +665,14 @@ func Stream(w http.ResponseWriter, r *http.Request, v interface{}) {
 
 		default: // equivalent to: case v, ok := <-stream
 			if !ok {
-				w.Write([]byte("event: EOF\n\n")) //nolint:errcheck
+				fmt.Fprintf(w, "event: %s\n", StreamCloseEvent)
+				if StreamCloseData != "" {
+					fmt.Fprintf(w, "data: %s\n", StreamCloseData)
+				}
+				w.Write([]byte("\n")) //nolint:errcheck
+				if f, ok := w.(http.Flusher); ok {
+					f.Flush()
+				}
 				return
 			}
 			v := recv.Interface()
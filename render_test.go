@@ -20,7 +20,17 @@
 package render_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"encoding/xml"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/enverbisevac/render"
@@ -166,3 +176,485 @@ func TestBlob(t *testing.T) {
 		})
 	}
 }
+
+func TestBlob_WithTee(t *testing.T) {
+	tee := &bytes.Buffer{}
+	r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	r.Header.Set(render.AcceptHeader, render.ApplicationJSON)
+	w := httptest.NewRecorder()
+
+	render.Render(w, r, map[string]string{"name": "Enver"}, render.WithTee(tee))
+
+	utest.Equals(t, http.StatusOK, w.Code)
+	utest.Equals(t, `{"name":"Enver"}`+"\n", w.Body.String())
+	utest.Equals(t, w.Body.Bytes(), tee.Bytes())
+}
+
+func TestBlob_WithTeeCompression(t *testing.T) {
+	render.Compression = true
+	defer func() {
+		render.Compression = false
+	}()
+
+	tee := &bytes.Buffer{}
+	r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	r.Header.Set(render.AcceptHeader, render.ApplicationJSON)
+	r.Header.Set(render.AcceptEncodingHeader, "identity;q=0, gzip")
+	w := httptest.NewRecorder()
+
+	render.Render(w, r, map[string]string{"name": "Enver"}, render.WithTee(tee))
+
+	utest.Equals(t, render.EncodingGzip, w.Header().Get(render.ContentEncodingHeader))
+	zr, err := gzip.NewReader(w.Body)
+	utest.OK(t, err)
+	body, err := io.ReadAll(zr)
+	utest.OK(t, err)
+	utest.Equals(t, `{"name":"Enver"}`+"\n", string(body))
+	utest.Equals(t, `{"name":"Enver"}`+"\n", tee.String())
+}
+
+func TestRenderStatus(t *testing.T) {
+	r := &http.Request{
+		URL: &url.URL{},
+		Header: http.Header{
+			render.AcceptHeader: []string{render.ApplicationJSON},
+		},
+	}
+	w := httptest.NewRecorder()
+
+	render.RenderStatus(w, r, http.StatusCreated, map[string]string{"name": "Enver"}, http.StatusAccepted)
+
+	utest.Equals(t, http.StatusCreated, w.Code)
+	utest.Equals(t, render.ApplicationJSONExt, w.Header().Get(render.ContentTypeHeader))
+	utest.Equals(t, `{"name":"Enver"}`+"\n", w.Body.String())
+}
+
+func TestDefaultResponder_PlainTextAndUnknown(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{
+			name:        "explicit text/plain renders plain text",
+			accept:      render.TextPlain,
+			contentType: "text/plain; charset=utf-8",
+			body:        "Enver",
+		},
+		{
+			name:        "unknown accept renders default content type",
+			accept:      "garbage",
+			contentType: "application/xml; charset=utf-8",
+			body:        xml.Header + "<string>Enver</string>",
+		},
+	}
+
+	refDefaultContentType := render.DefaultContentType
+	render.DefaultContentType = render.ContentTypeXML
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &http.Request{
+				URL: &url.URL{},
+				Header: http.Header{
+					render.AcceptHeader: []string{tt.accept},
+				},
+			}
+			w := httptest.NewRecorder()
+
+			render.DefaultResponder(w, r, "Enver")
+
+			utest.Equals(t, tt.contentType, w.Header().Get(render.ContentTypeHeader))
+			utest.Equals(t, tt.body, w.Body.String())
+		})
+	}
+	render.DefaultContentType = refDefaultContentType
+}
+
+func TestDefaultStatusByMethod(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		v      interface{}
+		params []interface{}
+		status int
+		body   string
+	}{
+		{
+			name:   "POST without status",
+			method: http.MethodPost,
+			v:      map[string]string{"name": "Enver"},
+			status: http.StatusCreated,
+			body:   `{"name":"Enver"}` + "\n",
+		},
+		{
+			name:   "DELETE with nil value",
+			method: http.MethodDelete,
+			v:      nil,
+			status: http.StatusNoContent,
+			body:   "",
+		},
+		{
+			name:   "POST with explicit status",
+			method: http.MethodPost,
+			v:      map[string]string{"name": "Enver"},
+			params: []interface{}{http.StatusOK},
+			status: http.StatusOK,
+			body:   `{"name":"Enver"}` + "\n",
+		},
+		{
+			name:   "GET without mapped status",
+			method: http.MethodGet,
+			v:      map[string]string{"name": "Enver"},
+			status: http.StatusOK,
+			body:   `{"name":"Enver"}` + "\n",
+		},
+	}
+
+	refDefaultStatusByMethod := render.DefaultStatusByMethod
+	render.DefaultStatusByMethod = map[string]int{
+		http.MethodPost:   http.StatusCreated,
+		http.MethodDelete: http.StatusNoContent,
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/users", nil)
+			r.Header.Set(render.AcceptHeader, render.ApplicationJSON)
+			w := httptest.NewRecorder()
+
+			render.Render(w, r, tt.v, tt.params...)
+
+			utest.Equals(t, tt.status, w.Code)
+			utest.Equals(t, tt.body, w.Body.String())
+		})
+	}
+	render.DefaultStatusByMethod = refDefaultStatusByMethod
+}
+
+func TestFile_ContentDisposition(t *testing.T) {
+	fullPath := filepath.Join(t.TempDir(), "résumé 2022.txt")
+	err := os.WriteFile(fullPath, []byte("Enver"), 0o600)
+	utest.OK(t, err)
+
+	r := httptest.NewRequest(http.MethodGet, "/download", nil)
+	w := httptest.NewRecorder()
+
+	render.File(w, r, fullPath)
+
+	utest.Equals(t, http.StatusOK, w.Code)
+	utest.Equals(t, `attachment; filename="resume 2022.txt"; filename*=UTF-8''r%C3%A9sum%C3%A9%202022.txt`,
+		w.Header().Get("Content-Disposition"))
+	utest.Equals(t, "Enver", w.Body.String())
+}
+
+func TestFile_RangeNotSatisfiable(t *testing.T) {
+	fullPath := filepath.Join(t.TempDir(), "short.txt")
+	utest.OK(t, os.WriteFile(fullPath, []byte("Enver"), 0o600))
+
+	r := httptest.NewRequest(http.MethodGet, "/download", nil)
+	r.Header.Set(render.RangeHeader, "bytes=9999-")
+	w := httptest.NewRecorder()
+
+	render.File(w, r, fullPath)
+
+	utest.Equals(t, http.StatusRequestedRangeNotSatisfiable, w.Code)
+	utest.Equals(t, "bytes */5", w.Header().Get(render.ContentRangeHeader))
+}
+
+func TestFile_Gzip(t *testing.T) {
+	render.Compression = true
+	defer func() {
+		render.Compression = false
+	}()
+
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "users.json")
+	utest.OK(t, os.WriteFile(jsonPath, []byte(`{"name":"Enver"}`), 0o600))
+	zipPath := filepath.Join(dir, "users.zip")
+	utest.OK(t, os.WriteFile(zipPath, []byte("PK"), 0o600))
+
+	r := httptest.NewRequest(http.MethodGet, "/download", nil)
+	r.Header.Set(render.AcceptEncodingHeader, "gzip, deflate")
+	r.Header.Set(render.RangeHeader, "bytes=0-3")
+	w := httptest.NewRecorder()
+
+	render.File(w, r, jsonPath)
+
+	utest.Equals(t, http.StatusOK, w.Code)
+	utest.Equals(t, render.EncodingGzip, w.Header().Get(render.ContentEncodingHeader))
+	utest.Equals(t, render.AcceptEncodingHeader, w.Header().Get(render.VaryHeader))
+	utest.Equals(t, "", w.Header().Get("Content-Length"))
+	zr, err := gzip.NewReader(w.Body)
+	utest.OK(t, err)
+	body, err := io.ReadAll(zr)
+	utest.OK(t, err)
+	utest.Equals(t, `{"name":"Enver"}`, string(body))
+
+	w = httptest.NewRecorder()
+	render.File(w, httptest.NewRequest(http.MethodGet, "/download", nil), jsonPath)
+	utest.Equals(t, "", w.Header().Get(render.ContentEncodingHeader))
+	utest.Equals(t, `{"name":"Enver"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	render.File(w, r, zipPath)
+	utest.Equals(t, "", w.Header().Get(render.ContentEncodingHeader))
+}
+
+func TestCreatedEmpty(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set(render.ContentTypeHeader, render.ApplicationJSONExt)
+
+	render.CreatedEmpty(w, "/users/1")
+
+	utest.Equals(t, http.StatusCreated, w.Code)
+	utest.Equals(t, "/users/1", w.Header().Get(render.LocationHeader))
+	utest.Equals(t, "", w.Header().Get(render.ContentTypeHeader))
+	utest.Equals(t, "0", w.Header().Get("Content-Length"))
+	utest.Equals(t, 0, w.Body.Len())
+}
+
+type encoderFunc func(v interface{}) error
+
+func (f encoderFunc) Encode(v interface{}) error {
+	return f(v)
+}
+
+func TestDefaultResponder_PrettyCustomEncoder(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoder func(w io.Writer) render.Encoder
+	}{
+		{
+			name: "encoder with SetIndent",
+			encoder: func(w io.Writer) render.Encoder {
+				enc := json.NewEncoder(w)
+				enc.SetEscapeHTML(false)
+				return enc
+			},
+		},
+		{
+			name: "encoder without SetIndent",
+			encoder: func(w io.Writer) render.Encoder {
+				return encoderFunc(func(v interface{}) error {
+					enc := json.NewEncoder(w)
+					enc.SetEscapeHTML(false)
+					return enc.Encode(v)
+				})
+			},
+		},
+	}
+
+	refJSONEncoder := render.JSONEncoder
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			render.JSONEncoder = tt.encoder
+			r := httptest.NewRequest(http.MethodGet, "/users/1?pretty", nil)
+			r.Header.Set(render.AcceptHeader, render.ApplicationJSON)
+			w := httptest.NewRecorder()
+
+			render.Render(w, r, map[string]string{"name": "<b>Enver</b>"})
+
+			utest.Equals(t, "{\n  \"name\": \"<b>Enver</b>\"\n}\n", w.Body.String())
+		})
+	}
+	render.JSONEncoder = refJSONEncoder
+}
+
+func TestDefaultResponder_Pretty(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		accept string
+		body   string
+	}{
+		{
+			name:   "accept pretty parameter",
+			target: "/users/1",
+			accept: "application/json; pretty=true",
+			body:   "{\n  \"name\": \"Enver\"\n}\n",
+		},
+		{
+			name:   "pretty query param",
+			target: "/users/1?pretty",
+			accept: render.ApplicationJSON,
+			body:   "{\n  \"name\": \"Enver\"\n}\n",
+		},
+		{
+			name:   "accept pretty parameter false",
+			target: "/users/1",
+			accept: "application/json; pretty=false",
+			body:   `{"name":"Enver"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			r.Header.Set(render.AcceptHeader, tt.accept)
+			w := httptest.NewRecorder()
+
+			render.Render(w, r, map[string]string{"name": "Enver"})
+
+			utest.Equals(t, render.ApplicationJSONExt, w.Header().Get(render.ContentTypeHeader))
+			utest.Equals(t, tt.body, w.Body.String())
+		})
+	}
+}
+
+func TestUnwrapSingle(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+	tests := []struct {
+		name string
+		v    []user
+		body string
+	}{
+		{
+			name: "one element slice renders object",
+			v:    []user{{Name: "Enver"}},
+			body: `{"name":"Enver"}` + "\n",
+		},
+		{
+			name: "two element slice renders array",
+			v:    []user{{Name: "Enver"}, {Name: "Joe"}},
+			body: `[{"name":"Enver"},{"name":"Joe"}]` + "\n",
+		},
+	}
+
+	refUnwrapSingle := render.UnwrapSingle
+	render.UnwrapSingle = true
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/users", nil)
+			r.Header.Set(render.AcceptHeader, render.ApplicationJSON)
+			w := httptest.NewRecorder()
+
+			render.Render(w, r, tt.v)
+
+			utest.Equals(t, tt.body, w.Body.String())
+		})
+	}
+	render.UnwrapSingle = refUnwrapSingle
+}
+
+func TestCollectionEnvelope(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+	type meta struct {
+		Count int `json:"count"`
+	}
+	tests := []struct {
+		name string
+		v    interface{}
+		body string
+	}{
+		{
+			name: "slice is wrapped",
+			v:    []user{{Name: "Enver"}, {Name: "Joe"}},
+			body: `{"data":[{"name":"Enver"},{"name":"Joe"}],"meta":{"count":2}}` + "\n",
+		},
+		{
+			name: "struct is not wrapped",
+			v:    user{Name: "Enver"},
+			body: `{"name":"Enver"}` + "\n",
+		},
+	}
+
+	refCollectionEnvelope := render.CollectionEnvelope
+	render.CollectionEnvelope = func(r *http.Request, v interface{}) interface{} {
+		return struct {
+			Data interface{} `json:"data"`
+			Meta meta        `json:"meta"`
+		}{
+			Data: v,
+			Meta: meta{Count: reflect.ValueOf(v).Len()},
+		}
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/users", nil)
+			r.Header.Set(render.AcceptHeader, render.ApplicationJSON)
+			w := httptest.NewRecorder()
+
+			render.Render(w, r, tt.v)
+
+			utest.Equals(t, tt.body, w.Body.String())
+		})
+	}
+	render.CollectionEnvelope = refCollectionEnvelope
+}
+
+func TestStripNulls(t *testing.T) {
+	type address struct {
+		City *string `json:"city"`
+		Zip  string  `json:"zip"`
+	}
+	type user struct {
+		Name      string    `json:"name"`
+		Nickname  *string   `json:"nickname"`
+		Address   address   `json:"address"`
+		Addresses []address `json:"addresses"`
+		Tags      []*string `json:"tags"`
+	}
+
+	render.StripNulls = true
+	defer func() {
+		render.StripNulls = false
+	}()
+
+	tests := []struct {
+		name   string
+		target string
+		body   string
+	}{
+		{
+			name:   "compact",
+			target: "/users/1",
+			body: `{"name":"Enver","address":{"zip":"71000"},"addresses":[{"zip":"71000"}],"tags":[null]}` +
+				"\n",
+		},
+		{
+			name:   "pretty",
+			target: "/users/1?pretty",
+			body: "{\n  \"name\": \"Enver\",\n  \"address\": {\n    \"zip\": \"71000\"\n  }," +
+				"\n  \"addresses\": [\n    {\n      \"zip\": \"71000\"\n    }\n  ],\n  \"tags\": [\n    null\n  ]\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			r.Header.Set(render.AcceptHeader, render.ApplicationJSON)
+			w := httptest.NewRecorder()
+
+			render.Render(w, r, user{
+				Name:      "Enver",
+				Address:   address{Zip: "71000"},
+				Addresses: []address{{Zip: "71000"}},
+				Tags:      []*string{nil},
+			})
+
+			utest.Equals(t, tt.body, w.Body.String())
+		})
+	}
+}
+
+func TestStream_CloseEvent(t *testing.T) {
+	refEvent, refData := render.StreamCloseEvent, render.StreamCloseData
+	render.StreamCloseEvent = "close"
+	render.StreamCloseData = `{"reason":"shutdown"}`
+	defer func() {
+		render.StreamCloseEvent, render.StreamCloseData = refEvent, refData
+	}()
+
+	ch := make(chan string)
+	close(ch)
+
+	r := httptest.NewRequest(http.MethodGet, "/events", nil)
+	w := httptest.NewRecorder()
+
+	render.Stream(w, r, ch)
+
+	utest.Equals(t, "event: close\ndata: {\"reason\":\"shutdown\"}\n\n", w.Body.String())
+	utest.Assert(t, w.Flushed, "expected response to be flushed")
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryAfterHeader represents Retry-After key in header
+const RetryAfterHeader = "Retry-After"
+
+// ServiceUnavailable renders 503 Service Unavailable error response with
+// Retry-After header set to retryAfter rounded up to seconds.
+func ServiceUnavailable(w http.ResponseWriter, r *http.Request, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	w.Header().Set(RetryAfterHeader, strconv.Itoa(seconds))
+	Error(w, r, ErrServiceUnavailable, http.StatusServiceUnavailable)
+}
+
+// ShutdownMiddleware returns middleware which rejects requests with
+// ServiceUnavailable while shutdown returns true, for example:
+//
+//	var shuttingDown int32
+//	mw := render.ShutdownMiddleware(func() bool {
+//		return atomic.LoadInt32(&shuttingDown) == 1
+//	}, 30*time.Second)
+func ShutdownMiddleware(shutdown func() bool, retryAfter time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if shutdown() {
+				ServiceUnavailable(w, r, retryAfter)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
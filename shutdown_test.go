@@ -0,0 +1,86 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
+)
+
+func TestServiceUnavailable(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{
+			name:        "json",
+			accept:      render.ApplicationJSON,
+			contentType: render.ApplicationJSONExt,
+			body:        `{"message":"service unavailable"}` + "\n",
+		},
+		{
+			name:        "plain text",
+			accept:      render.TextPlain,
+			contentType: "text/plain; charset=utf-8",
+			body:        "{service unavailable  }",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set(render.AcceptHeader, tt.accept)
+			w := httptest.NewRecorder()
+
+			render.ServiceUnavailable(w, r, 1500*time.Millisecond)
+
+			utest.Equals(t, http.StatusServiceUnavailable, w.Code)
+			utest.Equals(t, "2", w.Header().Get(render.RetryAfterHeader))
+			utest.Equals(t, tt.contentType, w.Header().Get(render.ContentTypeHeader))
+			utest.Equals(t, tt.body, w.Body.String())
+		})
+	}
+}
+
+func TestShutdownMiddleware(t *testing.T) {
+	shutdown := false
+	handler := render.ShutdownMiddleware(func() bool {
+		return shutdown
+	}, 30*time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		render.NoContent(w)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	utest.Equals(t, http.StatusNoContent, w.Code)
+
+	shutdown = true
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	utest.Equals(t, http.StatusServiceUnavailable, w.Code)
+	utest.Equals(t, "30", w.Header().Get(render.RetryAfterHeader))
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// StripNulls removes object keys with null values from encoded JSON
+// responses, including nested objects and objects in arrays. Null array
+// elements are kept.
+var StripNulls = false
+
+// stripNulls removes keys with null values from JSON document b. Indented
+// documents are indented again after stripping.
+func stripNulls(b []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(b)
+	stripped, err := stripNullsValue(trimmed)
+	if err != nil {
+		return nil, err
+	}
+
+	return indentLike(b, stripped)
+}
+
+// indentLike indents compact JSON document b the same way as original
+// document orig, which is indented when it spans multiple lines. Trailing
+// newline of orig is kept.
+func indentLike(orig, b []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(orig)
+	if lines := bytes.SplitN(trimmed, []byte("\n"), 3); len(lines) > 1 {
+		indent := lines[1][:len(lines[1])-len(bytes.TrimLeft(lines[1], " \t"))]
+		buf := &bytes.Buffer{}
+		if err := json.Indent(buf, b, "", string(indent)); err != nil {
+			return nil, err
+		}
+		b = buf.Bytes()
+	}
+	if bytes.HasSuffix(orig, []byte("\n")) {
+		b = append(b, '\n')
+	}
+	return b, nil
+}
+
+// stripNullsValue returns compact JSON value b without null object keys.
+func stripNullsValue(b []byte) ([]byte, error) {
+	switch {
+	case len(b) > 0 && b[0] == '{':
+		return stripNullsObject(b)
+	case len(b) > 0 && b[0] == '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(b, &items); err != nil {
+			return nil, err
+		}
+		buf := &bytes.Buffer{}
+		buf.WriteByte('[')
+		for i, item := range items {
+			value, err := stripNullsValue(item)
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.Write(value)
+		}
+		buf.WriteByte(']')
+		return buf.Bytes(), nil
+	default:
+		return b, nil
+	}
+}
+
+// stripNullsObject returns compact JSON object b without null keys. Keys are
+// copied as encoded to keep their order and escaping.
+func stripNullsObject(b []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for dec.More() {
+		start := dec.InputOffset()
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		key := bytes.TrimLeft(b[start:dec.InputOffset()], " \t\r\n,")
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		if string(raw) == "null" {
+			continue
+		}
+		value, err := stripNullsValue(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
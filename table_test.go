@@ -0,0 +1,50 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
+)
+
+func TestTable(t *testing.T) {
+	type user struct {
+		ID    int
+		Name  string
+		Email string `csv:"E-mail"`
+	}
+	w := httptest.NewRecorder()
+
+	render.Table(w, []user{
+		{ID: 1, Name: "Enver", Email: "enver@example.com"},
+		{ID: 12, Name: "Jo", Email: "jo@example.com"},
+	})
+
+	utest.Equals(t, http.StatusOK, w.Code)
+	utest.Equals(t, "text/plain; charset=utf-8", w.Header().Get(render.ContentTypeHeader))
+	utest.Equals(t, ""+
+		"ID  Name   E-mail\n"+
+		"1   Enver  enver@example.com\n"+
+		"12  Jo     jo@example.com\n", w.Body.String())
+}
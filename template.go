@@ -135,6 +135,8 @@ func templateFactory(w http.ResponseWriter, factory engine, v interface{}, ct st
 				if t == nil {
 					t.set(v)
 				}
+			case *http.Request:
+				// used for charset negotiation
 			default:
 				newParams = append(newParams, value)
 			}
@@ -152,7 +154,7 @@ func templateFactory(w http.ResponseWriter, factory engine, v interface{}, ct st
 		if t, err = factory.funcs(TemplateFuncs).parse(tmpl); err == nil {
 			err = t.execute(&buf, v)
 		}
-	default:
+	case buf.Len() == 0:
 		_, _ = buf.WriteString(fmt.Sprintf("%v", v))
 	}
 
@@ -160,6 +162,24 @@ func templateFactory(w http.ResponseWriter, factory engine, v interface{}, ct st
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	b, ct, ok := transcodeText(w, paramsRequest(params), buf.Bytes(), ct)
+	if !ok {
+		notAcceptable(w)
+		return
+	}
+	if exceedsMaxResponse(w, b) {
+		return
+	}
 
-	Blob(w, buf.Bytes(), append(newParams, ContentTypeHeader, ct)...)
+	Blob(w, b, append(newParams, ContentTypeHeader, ct)...)
+}
+
+// paramsRequest returns first request from params or nil.
+func paramsRequest(params []interface{}) *http.Request {
+	for _, param := range params {
+		if r, ok := param.(*http.Request); ok {
+			return r
+		}
+	}
+	return nil
 }
@@ -0,0 +1,44 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"net/http"
+	"strings"
+)
+
+// AuthorizationHeader represents Authorization key in header
+const AuthorizationHeader = "Authorization"
+
+// BearerToken returns token from request Authorization header with Bearer
+// scheme. ErrInvalidToken is returned when header is missing or malformed,
+// which Error maps to 400 Bad Request.
+func BearerToken(r *http.Request) (string, error) {
+	parts := strings.SplitN(strings.TrimSpace(r.Header.Get(AuthorizationHeader)), " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return "", ErrInvalidToken
+	}
+
+	token := strings.TrimSpace(parts[1])
+	if token == "" {
+		return "", ErrInvalidToken
+	}
+	return token, nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
+)
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		token  string
+		err    error
+	}{
+		{
+			name: "valid bearer token",
+			header: http.Header{
+				render.AuthorizationHeader: []string{"Bearer x"},
+			},
+			token: "x",
+		},
+		{
+			name:   "missing header",
+			header: http.Header{},
+			err:    render.ErrInvalidToken,
+		},
+		{
+			name: "malformed scheme",
+			header: http.Header{
+				render.AuthorizationHeader: []string{"Basic dXNlcjpwYXNz"},
+			},
+			err: render.ErrInvalidToken,
+		},
+		{
+			name: "empty token",
+			header: http.Header{
+				render.AuthorizationHeader: []string{"Bearer "},
+			},
+			err: render.ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := render.BearerToken(&http.Request{Header: tt.header})
+			utest.Assert(t, errors.Is(err, tt.err), "BearerToken() error = %v, wantErr %v", err, tt.err)
+			utest.Equals(t, tt.token, token)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/BurntSushi/toml"
+)
+
+var (
+	// TOMLEncoder is a package variable set to default TOML encoder
+	TOMLEncoder = DefaultTOMLEncoder
+	// TOMLDecoder is a package-level variable set to our default TOML decoder
+	// function.
+	TOMLDecoder = DefaultTOMLDecoder
+)
+
+// DefaultTOMLEncoder creates default TOML encoder
+func DefaultTOMLEncoder(w io.Writer) Encoder {
+	return toml.NewEncoder(w)
+}
+
+// tomlDecoder adapts toml.Decoder to Decoder interface.
+type tomlDecoder struct {
+	dec *toml.Decoder
+}
+
+// Decode decodes TOML data into v, metadata is discarded.
+func (d tomlDecoder) Decode(v interface{}) error {
+	_, err := d.dec.Decode(v)
+	return err
+}
+
+// DefaultTOMLDecoder returns new TOML decoder for decoding
+// TOML data.
+func DefaultTOMLDecoder(r io.Reader) Decoder {
+	return tomlDecoder{dec: toml.NewDecoder(r)}
+}
+
+// DecodeTOML decodes a given reader into an interface using the toml decoder.
+func DecodeTOML(r io.Reader, v interface{}) error {
+	defer io.Copy(io.Discard, r) //nolint:errcheck
+	return TOMLDecoder(r).Decode(v)
+}
+
+// TOML marshals 'v' to TOML, setting the Content-Type as application/toml.
+func TOML(w http.ResponseWriter, v interface{}, params ...interface{}) {
+	if requestCanceled(w) {
+		return
+	}
+	buf := &bytes.Buffer{}
+	if err := TOMLEncoder(buf).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if requestCanceled(w) || exceedsMaxResponse(w, buf.Bytes()) {
+		return
+	}
+	Blob(w, buf.Bytes(), append(params, ContentTypeHeader, ApplicationTOML)...)
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
+)
+
+type tomlConfig struct {
+	Name    string   `toml:"name"`
+	Port    int      `toml:"port"`
+	Servers []string `toml:"servers"`
+}
+
+func TestTOML_RoundTrip(t *testing.T) {
+	want := tomlConfig{
+		Name:    "api",
+		Port:    8080,
+		Servers: []string{"alpha", "beta"},
+	}
+
+	w := httptest.NewRecorder()
+	render.TOML(w, want)
+
+	utest.Equals(t, http.StatusOK, w.Code)
+	utest.Equals(t, render.ApplicationTOML, w.Header().Get(render.ContentTypeHeader))
+
+	r := httptest.NewRequest(http.MethodPut, "/config", bytes.NewReader(w.Body.Bytes()))
+	r.Header.Set(render.ContentTypeHeader, render.ApplicationTOML)
+
+	got := tomlConfig{}
+	utest.OK(t, render.Decode(r, &got))
+	utest.Equals(t, want, got)
+}
+
+func TestTOML_Negotiation(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		accept string
+	}{
+		{
+			name:   "accept header",
+			target: "/config",
+			accept: render.ApplicationTOML,
+		},
+		{
+			name:   "format query param",
+			target: "/config?format=toml",
+			accept: render.ApplicationJSON,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			r.Header.Set(render.AcceptHeader, tt.accept)
+			w := httptest.NewRecorder()
+
+			render.Render(w, r, tomlConfig{Name: "api", Port: 8080})
+
+			utest.Equals(t, render.ApplicationTOML, w.Header().Get(render.ContentTypeHeader))
+			utest.Equals(t, "name = \"api\"\nport = 8080\n", w.Body.String())
+		})
+	}
+}
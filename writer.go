@@ -0,0 +1,123 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"net/http"
+)
+
+// responseWriter wraps http.ResponseWriter with request being rendered and
+// records status code and number of bytes written.
+type responseWriter struct {
+	http.ResponseWriter
+	r *http.Request
+	// unlimited disables MaxResponseBytes check, used when rendering
+	// ErrResponseTooLarge itself.
+	unlimited bool
+	status    int
+	bytes     int
+}
+
+// wrapWriter returns w wrapped in responseWriter unless it is already
+// wrapped. Second return value reports whether new wrapper was created.
+func wrapWriter(w http.ResponseWriter, r *http.Request) (*responseWriter, bool) {
+	if rw, ok := w.(*responseWriter); ok {
+		return rw, false
+	}
+	return &responseWriter{
+		ResponseWriter: w,
+		r:              r,
+	}, true
+}
+
+// WriteHeader records status code and sends response header.
+func (rw *responseWriter) WriteHeader(status int) {
+	if rw.status == 0 {
+		rw.status = status
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+// Write records number of bytes written to the response.
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytes += n
+	return n, err
+}
+
+// Status returns status code written to the response, zero when nothing was
+// written.
+func (rw *responseWriter) Status() int {
+	return rw.status
+}
+
+// Flush sends any buffered data to the client if underlying writer supports
+// it.
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// requestCanceled reports whether context of the request rendered to w is
+// done, for example when client disconnected. OnError is called with context
+// error as ClientError in that case.
+func requestCanceled(w http.ResponseWriter) bool {
+	rw, ok := w.(*responseWriter)
+	if !ok {
+		return false
+	}
+	if err := rw.r.Context().Err(); err != nil {
+		OnError(rw.r, err, ClientError)
+		return true
+	}
+	return false
+}
+
+// exceedsMaxResponse checks size of encoded response b against
+// MaxResponseBytes. When the limit is exceeded ErrResponseTooLarge error
+// response is written and true is returned. OnError is not called when w is
+// not wrapped by DefaultResponder, as there is no request to pass to it.
+func exceedsMaxResponse(w http.ResponseWriter, b []byte) bool {
+	if MaxResponseBytes <= 0 || len(b) <= MaxResponseBytes {
+		return false
+	}
+
+	rw, ok := w.(*responseWriter)
+	if ok && rw.unlimited {
+		return false
+	}
+
+	if !ok {
+		http.Error(w, ErrResponseTooLarge.Error(), http.StatusInternalServerError)
+		return true
+	}
+
+	// Error calls OnError
+	Error(&responseWriter{
+		ResponseWriter: rw,
+		r:              rw.r,
+		unlimited:      true,
+	}, rw.r, ErrResponseTooLarge)
+	return true
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
+)
+
+func TestMaxResponseBytes(t *testing.T) {
+	r := &http.Request{
+		URL: &url.URL{},
+		Header: http.Header{
+			render.AcceptHeader: []string{render.ApplicationJSON},
+		},
+	}
+	v := map[string]string{
+		"name": strings.Repeat("x", 100),
+	}
+
+	var onErr error
+	refOnError := render.OnError
+	render.OnError = func(r *http.Request, err error, class render.ErrorClass) {
+		onErr = err
+	}
+	refMaxResponseBytes := render.MaxResponseBytes
+	render.MaxResponseBytes = 50
+
+	w := httptest.NewRecorder()
+	render.Render(w, r, v)
+
+	utest.Equals(t, http.StatusInternalServerError, w.Code)
+	utest.Equals(t, `{"message":"response too large"}`+"\n", w.Body.String())
+	utest.Assert(t, errors.Is(onErr, render.ErrResponseTooLarge), "expected ErrResponseTooLarge, got %v", onErr)
+
+	render.MaxResponseBytes = 0
+	onErr = nil
+
+	w = httptest.NewRecorder()
+	render.Render(w, r, v)
+
+	utest.Equals(t, http.StatusOK, w.Code)
+	utest.OK(t, onErr)
+
+	render.MaxResponseBytes = refMaxResponseBytes
+	render.OnError = refOnError
+}
+
+func TestCanceledRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+	}{
+		{
+			name:   "json",
+			accept: render.ApplicationJSON,
+		},
+		{
+			name:   "xml",
+			accept: render.ApplicationXML,
+		},
+	}
+
+	var onErr error
+	refOnError := render.OnError
+	render.OnError = func(r *http.Request, err error, class render.ErrorClass) {
+		onErr = err
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			r := httptest.NewRequest(http.MethodGet, "/users", nil).WithContext(ctx)
+			r.Header.Set(render.AcceptHeader, tt.accept)
+			w := httptest.NewRecorder()
+			onErr = nil
+
+			render.Render(w, r, map[string]string{"name": "Enver"})
+
+			utest.Equals(t, "", w.Body.String())
+			utest.Equals(t, "", w.Header().Get(render.ContentTypeHeader))
+			utest.Assert(t, errors.Is(onErr, context.Canceled), "expected context.Canceled, got %v", onErr)
+		})
+	}
+	render.OnError = refOnError
+}
+
+func TestAfterRender(t *testing.T) {
+	var (
+		calls  int
+		status int
+		bytes  int
+	)
+	refAfterRender := render.AfterRender
+	render.AfterRender = func(r *http.Request, s, b int) {
+		calls++
+		status = s
+		bytes = b
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/users", nil)
+	r.Header.Set(render.AcceptHeader, render.ApplicationJSON)
+	w := httptest.NewRecorder()
+
+	render.Render(w, r, map[string]string{"name": "Enver"}, http.StatusCreated)
+
+	utest.Equals(t, 1, calls)
+	utest.Equals(t, http.StatusCreated, status)
+	utest.Equals(t, w.Body.Len(), bytes)
+	utest.Equals(t, len(`{"name":"Enver"}`+"\n"), bytes)
+
+	refMaxResponseBytes := render.MaxResponseBytes
+	render.MaxResponseBytes = 10
+	calls = 0
+	w = httptest.NewRecorder()
+
+	render.Render(w, r, map[string]string{"name": "Enver"})
+
+	utest.Equals(t, 1, calls)
+	utest.Equals(t, http.StatusInternalServerError, status)
+	utest.Equals(t, w.Body.Len(), bytes)
+
+	render.MaxResponseBytes = refMaxResponseBytes
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r = httptest.NewRequest(http.MethodGet, "/users", nil).WithContext(ctx)
+	calls = 0
+	w = httptest.NewRecorder()
+
+	render.Render(w, r, map[string]string{"name": "Enver"})
+
+	utest.Equals(t, 1, calls)
+	utest.Equals(t, 0, status)
+	utest.Equals(t, 0, bytes)
+
+	render.AfterRender = refAfterRender
+}
+
+func TestMaxResponseBytes_Direct(t *testing.T) {
+	var calls int
+	refOnError := render.OnError
+	render.OnError = func(r *http.Request, err error, class render.ErrorClass) {
+		calls++
+		_ = r.URL.Path
+	}
+	refMaxResponseBytes := render.MaxResponseBytes
+	render.MaxResponseBytes = 10
+
+	w := httptest.NewRecorder()
+	render.JSON(w, map[string]string{"name": strings.Repeat("x", 100)})
+
+	utest.Equals(t, http.StatusInternalServerError, w.Code)
+	utest.Equals(t, render.ErrResponseTooLarge.Error()+"\n", w.Body.String())
+	utest.Equals(t, 0, calls)
+
+	render.MaxResponseBytes = refMaxResponseBytes
+	render.OnError = refOnError
+}
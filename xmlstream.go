@@ -0,0 +1,111 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+)
+
+// ErrNoOpenElement is returned when closing element on XMLStreamEncoder
+// without open elements.
+var ErrNoOpenElement = errors.New("render: no open xml element")
+
+// XMLStreamEncoder is a thin wrapper over xml.Encoder for writing large XML
+// documents incrementally.
+type XMLStreamEncoder struct {
+	w     http.ResponseWriter
+	enc   *xml.Encoder
+	stack []xml.StartElement
+}
+
+// XMLStream writes response header and XML declaration, setting the
+// Content-Type as application/xml, and returns encoder for writing document
+// body, for example:
+//
+//	s := render.XMLStream(w)
+//	s.Open("users")
+//	for rows.Next() {
+//		s.Element("user", user)
+//		s.Flush()
+//	}
+//	s.End()
+func XMLStream(w http.ResponseWriter, params ...interface{}) *XMLStreamEncoder {
+	Blob(w, []byte(xml.Header), append(params, ContentTypeHeader, "application/xml; charset=utf-8")...)
+	return &XMLStreamEncoder{
+		w:   w,
+		enc: xml.NewEncoder(w),
+	}
+}
+
+// Open writes start element with name and attributes.
+func (s *XMLStreamEncoder) Open(name string, attrs ...xml.Attr) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs}
+	if err := s.enc.EncodeToken(start); err != nil {
+		return err
+	}
+	s.stack = append(s.stack, start)
+	return nil
+}
+
+// Close writes end element for the last opened element.
+func (s *XMLStreamEncoder) Close() error {
+	if len(s.stack) == 0 {
+		return ErrNoOpenElement
+	}
+	start := s.stack[len(s.stack)-1]
+	if err := s.enc.EncodeToken(start.End()); err != nil {
+		return err
+	}
+	s.stack = s.stack[:len(s.stack)-1]
+	return nil
+}
+
+// Element writes v as element with name.
+func (s *XMLStreamEncoder) Element(name string, v interface{}) error {
+	return s.enc.EncodeElement(v, xml.StartElement{Name: xml.Name{Local: name}})
+}
+
+// Encode writes v using its default XML element name.
+func (s *XMLStreamEncoder) Encode(v interface{}) error {
+	return s.enc.Encode(v)
+}
+
+// Flush writes buffered XML to the response and flushes it to the client.
+func (s *XMLStreamEncoder) Flush() error {
+	if err := s.enc.Flush(); err != nil {
+		return err
+	}
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// End closes all open elements and flushes the response.
+func (s *XMLStreamEncoder) End() error {
+	for len(s.stack) > 0 {
+		if err := s.Close(); err != nil {
+			return err
+		}
+	}
+	return s.Flush()
+}
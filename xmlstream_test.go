@@ -0,0 +1,61 @@
+// Copyright (c) 2022 Enver Bisevac
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package render_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enverbisevac/render"
+	"github.com/enverbisevac/render/utest"
+)
+
+func TestXMLStream(t *testing.T) {
+	type user struct {
+		Name string `xml:"name"`
+	}
+	w := httptest.NewRecorder()
+
+	s := render.XMLStream(w)
+	utest.OK(t, s.Open("users", xml.Attr{Name: xml.Name{Local: "page"}, Value: "1"}))
+	for _, name := range []string{"Enver", "Joe"} {
+		utest.OK(t, s.Element("user", user{Name: name}))
+		utest.OK(t, s.Flush())
+	}
+	utest.OK(t, s.End())
+
+	utest.Equals(t, http.StatusOK, w.Code)
+	utest.Equals(t, "application/xml; charset=utf-8", w.Header().Get(render.ContentTypeHeader))
+	utest.Equals(t, xml.Header+`<users page="1"><user><name>Enver</name></user><user><name>Joe</name></user></users>`,
+		w.Body.String())
+	utest.Assert(t, w.Flushed, "expected response to be flushed")
+
+	var got struct {
+		Users []user `xml:"user"`
+	}
+	utest.OK(t, xml.Unmarshal(w.Body.Bytes(), &got))
+	utest.Equals(t, 2, len(got.Users))
+
+	err := s.Close()
+	utest.Assert(t, errors.Is(err, render.ErrNoOpenElement), "expected ErrNoOpenElement, got %v", err)
+}